@@ -0,0 +1,113 @@
+// Package alias provides the alias command for the gosw CLI.
+package alias
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kechako/gosw/env"
+	"github.com/spf13/cobra"
+)
+
+func Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "alias",
+		Short: "Manage aliases of Go versions",
+	}
+
+	cmd.AddCommand(
+		listCommand(),
+		setCommand(),
+		removeCommand(),
+	)
+
+	return cmd
+}
+
+func listCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List aliases",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			e := env.FromContext(cmd.Context())
+
+			aliases, err := e.Aliases()
+			if err != nil {
+				return err
+			}
+
+			for _, alias := range aliases {
+				if e.HasVersion(alias.Version) {
+					fmt.Printf("%s -> %s\n", alias.Name, alias.Version)
+				} else {
+					fmt.Printf("%s -> %s (not installed)\n", alias.Name, alias.Version)
+				}
+			}
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func setCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set <name> <version>",
+		Short: "Set an alias of a Go version",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			e := env.FromContext(cmd.Context())
+
+			v, err := env.ParseVersion(args[1])
+			if err != nil {
+				return fmt.Errorf("version syntax is not valid: %s", args[1])
+			}
+
+			if err := e.SetAlias(args[0], v); err != nil {
+				return err
+			}
+
+			if !e.HasVersion(v) {
+				fmt.Fprintf(os.Stderr, "warning: %s is not installed\n", v)
+			}
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func removeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rm <name>",
+		Short: "Remove an alias",
+		Args:  cobra.ExactArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+
+			e := env.FromContext(cmd.Context())
+			aliases, err := e.Aliases()
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveError
+			}
+
+			completions := make([]cobra.Completion, 0, len(aliases))
+			for _, alias := range aliases {
+				completions = append(completions, cobra.Completion(alias.Name))
+			}
+			return completions, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			e := env.FromContext(cmd.Context())
+
+			return e.RemoveAlias(args[0])
+		},
+	}
+
+	return cmd
+}
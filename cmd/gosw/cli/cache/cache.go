@@ -0,0 +1,21 @@
+// Package cache provides the cache command for the gosw CLI.
+package cache
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage downloaded archives",
+	}
+
+	cmd.AddCommand(
+		listCommand(),
+		verifyCommand(),
+		removeCommand(),
+	)
+
+	return cmd
+}
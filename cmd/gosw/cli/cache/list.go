@@ -0,0 +1,57 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kechako/gosw/cmd/gosw/cli/cliformat"
+	"github.com/kechako/gosw/env"
+	"github.com/kechako/table"
+	"github.com/spf13/cobra"
+)
+
+func listCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List downloaded archives",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			e := env.FromContext(cmd.Context())
+
+			archives, err := e.CachedArchives()
+			if err != nil {
+				return err
+			}
+
+			t := table.New(
+				&table.Column{Title: "Archive", Alignment: table.AlignLeft},
+				&table.Column{Title: "Version", Alignment: table.AlignLeft},
+				&table.Column{Title: "Size", Alignment: table.AlignRight},
+				&table.Column{Title: "Age", Alignment: table.AlignRight},
+				&table.Column{Title: "Installed", Alignment: table.AlignCenter},
+			)
+			var total int64
+			for _, a := range archives {
+				version := "-"
+				if a.Version != nil {
+					version = a.Version.String()
+				}
+				t.AddRow(
+					table.String(a.Name),
+					table.String(version),
+					table.String(cliformat.Bytes(a.Size)),
+					table.String(cliformat.Age(a.ModTime)),
+					table.Bool(a.Installed),
+				)
+				total += a.Size
+			}
+			t.Format(os.Stdout)
+
+			fmt.Printf("Total: %s\n", cliformat.Bytes(total))
+
+			return nil
+		},
+	}
+
+	return cmd
+}
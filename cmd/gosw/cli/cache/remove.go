@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kechako/gosw/cmd/gosw/cli/cliformat"
+	"github.com/kechako/gosw/env"
+	"github.com/spf13/cobra"
+)
+
+func removeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rm [flags] <version>",
+		Short: "Remove downloaded archives of a specific Go version",
+		Args:  cobra.ExactArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+
+			e := env.FromContext(cmd.Context())
+			archives, err := e.CachedArchives()
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveError
+			}
+
+			completions := make([]cobra.Completion, 0, len(archives))
+			for _, a := range archives {
+				if a.Version != nil && strings.HasPrefix(a.Version.String(), toComplete) {
+					completions = append(completions, cobra.Completion(a.Version.String()))
+				}
+			}
+			return completions, cobra.ShellCompDirectiveNoSpace
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			e := env.FromContext(cmd.Context())
+
+			v, err := e.ResolveVersion(args[0])
+			if err != nil {
+				return err
+			}
+
+			var opts []env.RemoveOption
+			if force, _ := cmd.Flags().GetBool("force"); force {
+				opts = append(opts, env.Force())
+			}
+
+			removed, err := e.RemoveCachedArchives(v, opts...)
+			if err != nil {
+				return err
+			}
+			if len(removed) == 0 {
+				return fmt.Errorf("archives of %s are held or in use by another process", v)
+			}
+
+			for _, a := range removed {
+				fmt.Printf("Removed %s (%s)\n", a.Name, cliformat.Bytes(a.Size))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolP("force", "f", false, "Remove the archives even if the version is held")
+
+	return cmd
+}
@@ -0,0 +1,50 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kechako/gosw/cmd/gosw/cli/clierrors"
+	"github.com/kechako/gosw/env"
+	"github.com/spf13/cobra"
+)
+
+func verifyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Verify checksums of downloaded archives",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			e := env.FromContext(cmd.Context())
+
+			archives, err := e.CachedArchives()
+			if err != nil {
+				return err
+			}
+
+			failed := 0
+			for _, a := range archives {
+				err := e.VerifyCachedArchive(a)
+				switch {
+				case err == nil:
+					fmt.Printf("%s: OK\n", a.Name)
+				case errors.Is(err, env.ErrUnknownArchive):
+					fmt.Printf("%s: SKIPPED (%v)\n", a.Name, err)
+				case errors.Is(err, env.ErrChecksumMismatch):
+					fmt.Printf("%s: FAILED (%v)\n", a.Name, err)
+					failed++
+				default:
+					return err
+				}
+			}
+
+			if failed > 0 {
+				return clierrors.Exit(fmt.Errorf("%d archive(s) failed verification", failed), 1)
+			}
+
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -14,7 +14,12 @@ func Command() *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			e := env.FromContext(cmd.Context())
 
-			if err := e.Clean(); err != nil {
+			var opts []env.RemoveOption
+			if force, _ := cmd.Flags().GetBool("force"); force {
+				opts = append(opts, env.Force())
+			}
+
+			if err := e.Clean(opts...); err != nil {
 				return err
 			}
 
@@ -22,5 +27,7 @@ func Command() *cobra.Command {
 		},
 	}
 
+	cmd.Flags().BoolP("force", "f", false, "Remove archives of held versions too")
+
 	return cmd
 }
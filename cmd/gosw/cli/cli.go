@@ -7,13 +7,26 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
-	"path/filepath"
+	"strconv"
 
+	"github.com/kechako/gosw/cmd/gosw/cli/alias"
+	"github.com/kechako/gosw/cmd/gosw/cli/cache"
 	"github.com/kechako/gosw/cmd/gosw/cli/clean"
 	"github.com/kechako/gosw/cmd/gosw/cli/clierrors"
+	"github.com/kechako/gosw/cmd/gosw/cli/cliformat"
+	"github.com/kechako/gosw/cmd/gosw/cli/config"
+	"github.com/kechako/gosw/cmd/gosw/cli/current"
+	"github.com/kechako/gosw/cmd/gosw/cli/history"
+	"github.com/kechako/gosw/cmd/gosw/cli/hold"
 	"github.com/kechako/gosw/cmd/gosw/cli/install"
+	"github.com/kechako/gosw/cmd/gosw/cli/link"
+	"github.com/kechako/gosw/cmd/gosw/cli/migrate"
+	"github.com/kechako/gosw/cmd/gosw/cli/outdated"
+	"github.com/kechako/gosw/cmd/gosw/cli/path"
+	"github.com/kechako/gosw/cmd/gosw/cli/unhold"
 	"github.com/kechako/gosw/cmd/gosw/cli/uninstall"
 	"github.com/kechako/gosw/cmd/gosw/cli/update"
+	"github.com/kechako/gosw/cmd/gosw/cli/upgrade"
 	"github.com/kechako/gosw/cmd/gosw/cli/use"
 	"github.com/kechako/gosw/cmd/gosw/cli/versions"
 	"github.com/kechako/gosw/env"
@@ -26,24 +39,113 @@ const (
 )
 
 func Main() {
-	defaultRoot, err := getDefaultRoot()
-	if err != nil {
-		printError(err)
-		os.Exit(1)
-	}
+	var e *env.Env
 
 	cmd := &cobra.Command{
 		Use:     appName,
 		Version: appVersion,
 		Short:   "gosw is a simple command-line interface for managing Go environment",
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			confDir, err := env.DefaultConfigDir()
+			if err != nil {
+				return clierrors.Exit(err, 1)
+			}
+			file, err := config.Load(confDir)
+			if err != nil {
+				return clierrors.Exit(err, 1)
+			}
+			// --user and --root override each other by the precedence of
+			// their sources, which are looked up before Apply sets the flags
+			flags := cmd.Root().PersistentFlags()
+			_, rootSource := config.Lookup(flags.Lookup("root"), file)
+			_, userSource := config.Lookup(flags.Lookup("user"), file)
+			if err := config.Apply(flags, file); err != nil {
+				return clierrors.Exit(err, 1)
+			}
+
 			root, err := cmd.Flags().GetString("root")
 			if err != nil {
-				root = defaultRoot
+				root = env.DefaultEnvRoot
+			}
+			if user, _ := cmd.Flags().GetBool("user"); user && userSource.Overrides(rootSource) {
+				root, err = env.DefaultUserEnvRoot()
+				if err != nil {
+					return clierrors.Exit(err, 1)
+				}
+			}
+			linkName, err := cmd.Flags().GetString("link-name")
+			if err != nil {
+				linkName = env.DefaultVersionLinkName
+			}
+			cacheDir, _ := cmd.Flags().GetString("cache-dir")
+			mirrors, _ := cmd.Flags().GetStringSlice("mirror")
+			maxCacheSize, err := cmd.Flags().GetString("max-cache-size")
+			if err != nil {
+				maxCacheSize = ""
+			}
+			var maxSize int64
+			if maxCacheSize != "" {
+				maxSize, err = cliformat.ParseBytes(maxCacheSize)
+				if err != nil {
+					return clierrors.Exit(err, 1)
+				}
+			}
+			removeArchive, _ := cmd.Flags().GetBool("remove-archive")
+			releasesTTL, err := cmd.Flags().GetDuration("releases-ttl")
+			if err != nil {
+				releasesTTL = env.DefaultReleasesTTL
+			}
+			offline, _ := cmd.Flags().GetBool("offline")
+			// shell completion must not wait for the network
+			if cmd.Name() == cobra.ShellCompRequestCmd || cmd.Name() == cobra.ShellCompNoDescRequestCmd {
+				offline = true
 			}
-			e, err := env.New(
+			lockTimeout, err := cmd.Flags().GetDuration("lock-timeout")
+			if err != nil {
+				lockTimeout = env.DefaultLockTimeout
+			}
+			minorLinks, _ := cmd.Flags().GetBool("minor-links")
+			commandDir, _ := cmd.Flags().GetString("command-dir")
+			systemRoot, _ := cmd.Flags().GetString("system-root")
+			installGroup, _ := cmd.Flags().GetString("install-group")
+			installUmask, _ := cmd.Flags().GetString("install-umask")
+			fallback, _ := cmd.Flags().GetString("fallback")
+			fallbackPolicy, err := env.ParseFallbackPolicy(fallback)
+			if err != nil {
+				return clierrors.Exit(err, 1)
+			}
+			var hooks env.Hooks
+			hooks.PostInstall, _ = cmd.Flags().GetString("hook-post-install")
+			hooks.PostUninstall, _ = cmd.Flags().GetString("hook-post-uninstall")
+			hooks.PostSwitch, _ = cmd.Flags().GetString("hook-post-switch")
+
+			opts := []env.Option{
 				env.WithEnvRoot(root),
-			)
+				env.WithVersionLinkName(linkName),
+				env.WithConfigDir(confDir),
+				env.WithCacheDir(cacheDir),
+				env.WithMirrors(mirrors...),
+				env.WithMaxCacheSize(maxSize),
+				env.WithRemoveArchive(removeArchive),
+				env.WithReleasesTTL(releasesTTL),
+				env.WithOffline(offline),
+				env.WithLockTimeout(lockTimeout),
+				env.WithMinorLinks(minorLinks),
+				env.WithCommandDir(commandDir),
+				env.WithHooks(hooks),
+				env.WithFallbackPolicy(fallbackPolicy),
+				env.WithSystemRoot(systemRoot),
+				env.WithInstallGroup(installGroup),
+			}
+			if installUmask != "" {
+				umask, err := strconv.ParseUint(installUmask, 8, 32)
+				if err != nil || umask > 0777 {
+					return clierrors.Exit(fmt.Errorf("invalid umask: %s", installUmask), 1)
+				}
+				opts = append(opts, env.WithInstallUmask(os.FileMode(umask)))
+			}
+
+			e, err = env.New(opts...)
 			if err != nil {
 				return clierrors.Exit(err, 1)
 			}
@@ -57,27 +159,67 @@ func Main() {
 	}
 
 	cmd.AddCommand(
+		alias.Command(),
+		cache.Command(),
 		clean.Command(),
+		config.Command(),
+		current.Command(),
+		history.Command(),
+		hold.Command(),
 		install.Command(),
+		link.Command(),
+		migrate.Command(),
 		versions.Command(),
+		outdated.Command(),
+		path.Command(),
 		uninstall.Command(),
+		unhold.Command(),
 		update.Command(),
+		upgrade.Command(),
 		use.Command(),
 	)
 
-	cmd.PersistentFlags().String("root", defaultRoot, "Set the root directory for gosw")
+	cmd.PersistentFlags().String("root", env.DefaultEnvRoot, "Set the root directory for gosw")
+	cmd.PersistentFlags().String("system-root", "", "Use the versions in the shared root in addition to the ones in the root")
+	cmd.PersistentFlags().String("install-group", "", "Set the group of installed files")
+	cmd.PersistentFlags().String("install-umask", "", "Set the umask of installed files, e.g. 002 (the archive permissions if empty)")
+	cmd.PersistentFlags().Bool("user", false, "Use the per-user root in $XDG_DATA_HOME/gosw unless the root is set with the same or higher precedence")
+	cmd.PersistentFlags().String("link-name", env.DefaultVersionLinkName, "Set the name of the link to the current version")
+	cmd.PersistentFlags().String("cache-dir", "", "Set the directory of downloaded archives (the user cache directory if empty)")
+	cmd.PersistentFlags().StringSlice("mirror", nil, "Download archives from the mirror base URLs in order")
+	cmd.PersistentFlags().String("max-cache-size", "", "Set the maximum size of downloaded archives, e.g. 2GiB (no limit if empty)")
+	cmd.PersistentFlags().Bool("remove-archive", false, "Remove a downloaded archive after it is extracted")
+	cmd.PersistentFlags().Duration("releases-ttl", env.DefaultReleasesTTL, "Update the list of available versions automatically if it is older than this (0 to disable)")
+	cmd.PersistentFlags().Bool("offline", false, "Do not update the list of available versions automatically")
+	cmd.PersistentFlags().String("fallback", string(env.DefaultFallbackPolicy), "Select the current version when it is uninstalled (stable, previous, minor or none)")
+	cmd.PersistentFlags().Duration("lock-timeout", env.DefaultLockTimeout, "Give up waiting for another gosw process after this")
+	cmd.PersistentFlags().Bool("minor-links", false, "Maintain links of minor lines such as go1.22 pointing to the newest installed patch versions")
+	cmd.PersistentFlags().String("command-dir", "", "Maintain versioned commands such as go1.22.7 in the directory (disabled if empty)")
+	cmd.PersistentFlags().String("hook-post-install", "", "Run the shell command after a version is installed")
+	cmd.PersistentFlags().String("hook-post-uninstall", "", "Run the shell command after a version is uninstalled")
+	cmd.PersistentFlags().String("hook-post-switch", "", "Run the shell command after the current version is switched")
 
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer cancel()
 
-	if err := cmd.ExecuteContext(ctx); err != nil {
+	err := cmd.ExecuteContext(ctx)
+
+	if e != nil {
+		if updateErr := e.AutoUpdateError(); updateErr != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to update the download list, run 'gosw update' to retry: %v\n", updateErr)
+		}
+	}
+
+	if err != nil {
 		code := 1
 		var exitCoder clierrors.ExitCoder
 		if errors.As(err, &exitCoder) {
 			code = exitCoder.ExitCode()
 		}
 
-		printError(err)
+		if !clierrors.Silent(err) {
+			printError(err)
+		}
 
 		if code != 0 {
 			os.Exit(code)
@@ -85,16 +227,6 @@ func Main() {
 	}
 }
 
-func getDefaultRoot() (string, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "", err
-	}
-	_ = home
-	return filepath.Join("/usr/local/go"), nil
-	//return filepath.Join(home, ".local/share/gosw"), nil
-}
-
 func printError(err error) {
 	fmt.Fprintf(os.Stderr, "error: %v\n", err)
 }
@@ -1,7 +1,10 @@
 // Package clierrors provides error handling utilities for command-line interfaces.
 package clierrors
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
 
 type ExitCoder interface {
 	error
@@ -17,6 +20,13 @@ func Exit(err error, code int) error {
 	return &exitError{Err: err, Code: code}
 }
 
+// Silent reports whether err is an exit error without an underlying error,
+// which only carries an exit code and should not be printed.
+func Silent(err error) bool {
+	var e *exitError
+	return errors.As(err, &e) && e.Err == nil
+}
+
 func (e *exitError) Error() string {
 	if e == nil {
 		return "<nil>"
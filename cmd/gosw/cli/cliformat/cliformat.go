@@ -0,0 +1,125 @@
+// Package cliformat provides formatting utilities for command-line interfaces.
+package cliformat
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Bytes formats a size in bytes in binary units, such as "1.50 GiB".
+func Bytes(value int64) string {
+	bytes := float64(value)
+
+	// snprintf below uses %4.2f, so 1023.99 MiB should be shown as 1.00 GiB
+	bytesAbs := math.Abs(bytes) / 1023.995 * 1024
+
+	const kib = uint64(1024)
+	const mib = uint64(1024 * kib)
+	const gib = uint64(1024 * mib)
+	const tib = uint64(1024 * gib)
+	const pib = uint64(1024 * tib)
+	const eib = uint64(1024 * pib)
+
+	var divisor uint64
+	var unit string
+
+	if bytesAbs >= float64(eib) {
+		divisor = eib
+		unit = "EiB"
+	} else if bytesAbs >= float64(pib) {
+		divisor = pib
+		unit = "PiB"
+	} else if bytesAbs >= float64(tib) {
+		divisor = tib
+		unit = "TiB"
+	} else if bytesAbs >= float64(gib) {
+		divisor = gib
+		unit = "GiB"
+	} else if bytesAbs >= float64(mib) {
+		divisor = mib
+		unit = "MiB"
+	} else if bytesAbs >= float64(kib) {
+		divisor = kib
+		unit = "KiB"
+	} else {
+		divisor = 1
+		unit = "bytes"
+	}
+
+	if divisor == 1 {
+		return fmt.Sprintf("%4d %s", int(bytes), unit)
+	} else {
+		v := bytes / float64(divisor)
+		if math.Abs(v) >= 100000.0 {
+			return fmt.Sprintf("%4.2e %s", v, unit)
+		} else {
+			return fmt.Sprintf("%4.2f %s", v, unit)
+		}
+	}
+}
+
+var byteUnits = []struct {
+	suffix string
+	size   int64
+}{
+	{"KiB", 1 << 10},
+	{"MiB", 1 << 20},
+	{"GiB", 1 << 30},
+	{"TiB", 1 << 40},
+	{"KB", 1000},
+	{"MB", 1000 * 1000},
+	{"GB", 1000 * 1000 * 1000},
+	{"TB", 1000 * 1000 * 1000 * 1000},
+	{"K", 1 << 10},
+	{"M", 1 << 20},
+	{"G", 1 << 30},
+	{"T", 1 << 40},
+	{"B", 1},
+}
+
+// ParseBytes parses a size such as "500MiB", "2G" or "1024".
+func ParseBytes(s string) (int64, error) {
+	str := strings.TrimSpace(s)
+	size := int64(1)
+	for _, u := range byteUnits {
+		if strings.HasSuffix(strings.ToUpper(str), strings.ToUpper(u.suffix)) {
+			str = strings.TrimSpace(str[:len(str)-len(u.suffix)])
+			size = u.size
+			break
+		}
+	}
+
+	value, err := strconv.ParseFloat(str, 64)
+	if err != nil || value < 0 {
+		return 0, errors.New("invalid size: " + s)
+	}
+
+	return int64(value * float64(size)), nil
+}
+
+// Age formats the elapsed time since t roughly, such as "3 days".
+func Age(t time.Time) string {
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return plural(int(d/time.Minute), "minute")
+	case d < 24*time.Hour:
+		return plural(int(d/time.Hour), "hour")
+	default:
+		return plural(int(d/(24*time.Hour)), "day")
+	}
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, unit)
+	}
+
+	return fmt.Sprintf("%d %ss", n, unit)
+}
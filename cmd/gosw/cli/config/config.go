@@ -0,0 +1,252 @@
+// Package config provides the config command for the gosw CLI.
+package config
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/kechako/gosw/env"
+	"github.com/kechako/table"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage the configuration of gosw",
+		Long: `Manage the configuration of gosw.
+
+Every global flag can be set in the config file, config.json in the config
+directory, and by an environment variable named after the flag, e.g.
+GOSW_CACHE_DIR for --cache-dir. A flag takes precedence over the environment
+variable, which takes precedence over the config file, which takes precedence
+over the default.`,
+		// the config commands do not need the env, and must work even if the
+		// config is broken
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+	}
+
+	cmd.AddCommand(
+		listCommand(),
+		getCommand(),
+		setCommand(),
+		unsetCommand(),
+		editCommand(),
+	)
+
+	return cmd
+}
+
+// settings returns the global flags, which are the settings.
+func settings(cmd *cobra.Command) *pflag.FlagSet {
+	return cmd.Root().PersistentFlags()
+}
+
+func completeSettings(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var completions []cobra.Completion
+	settings(cmd).VisitAll(func(flag *pflag.Flag) {
+		if strings.HasPrefix(flag.Name, toComplete) {
+			completions = append(completions, cobra.CompletionWithDesc(flag.Name, flag.Usage))
+		}
+	})
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+func loadFile() (string, File, error) {
+	confDir, err := env.DefaultConfigDir()
+	if err != nil {
+		return "", nil, err
+	}
+
+	f, err := Load(confDir)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return confDir, f, nil
+}
+
+func listCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the settings with their values and sources",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			_, f, err := loadFile()
+			if err != nil {
+				return err
+			}
+
+			t := table.New(
+				&table.Column{Title: "Setting", Alignment: table.AlignLeft},
+				&table.Column{Title: "Value", Alignment: table.AlignLeft},
+				&table.Column{Title: "Source", Alignment: table.AlignLeft},
+			)
+			settings(cmd).VisitAll(func(flag *pflag.Flag) {
+				value, source := Lookup(flag, f)
+				if value == "" {
+					value = "-"
+				}
+				if source == SourceEnv {
+					source = Source(EnvVar(flag.Name))
+				}
+				t.AddRow(
+					table.String(flag.Name),
+					table.String(value),
+					table.String(string(source)),
+				)
+			})
+			t.Format(os.Stdout)
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func getCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "get <setting>",
+		Short:             "Print the effective value of a setting",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeSettings,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			_, f, err := loadFile()
+			if err != nil {
+				return err
+			}
+
+			flag := settings(cmd).Lookup(args[0])
+			if flag == nil {
+				return fmt.Errorf("unknown setting: %s", args[0])
+			}
+
+			value, _ := Lookup(flag, f)
+			fmt.Println(value)
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func setCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "set <setting> <value>",
+		Short:             "Set a setting in the config file",
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeSettings,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			confDir, f, err := loadFile()
+			if err != nil {
+				return err
+			}
+
+			name, value := args[0], args[1]
+			flags := settings(cmd)
+			if flags.Lookup(name) == nil {
+				return fmt.Errorf("unknown setting: %s", name)
+			}
+			if err := flags.Set(name, value); err != nil {
+				return fmt.Errorf("invalid value of %s: %w", name, err)
+			}
+
+			f[name] = value
+
+			return f.Save(confDir)
+		},
+	}
+
+	return cmd
+}
+
+func unsetCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "unset <setting>",
+		Short:             "Remove a setting from the config file",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeSettings,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			confDir, f, err := loadFile()
+			if err != nil {
+				return err
+			}
+
+			if _, ok := f[args[0]]; !ok {
+				return fmt.Errorf("%s is not set in the config file", args[0])
+			}
+			delete(f, args[0])
+
+			return f.Save(confDir)
+		},
+	}
+
+	return cmd
+}
+
+func editCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "edit",
+		Short: "Edit the config file with $VISUAL or $EDITOR",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			confDir, f, err := loadFile()
+			if err != nil {
+				return err
+			}
+
+			path := Path(confDir)
+			if _, err := os.Stat(path); err != nil {
+				// create the file to edit
+				if err := f.Save(confDir); err != nil {
+					return err
+				}
+			}
+
+			editor := editorCommand()
+			c := exec.Command(editor[0], append(editor[1:], path)...)
+			c.Stdin = os.Stdin
+			c.Stdout = os.Stdout
+			c.Stderr = os.Stderr
+			if err := c.Run(); err != nil {
+				return fmt.Errorf("failed to run editor: %w", err)
+			}
+
+			f, err = Load(confDir)
+			if err != nil {
+				return err
+			}
+
+			return Apply(settings(cmd), f)
+		},
+	}
+
+	return cmd
+}
+
+// editorCommand returns the command line of the editor.
+func editorCommand() []string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(name)); len(fields) > 0 {
+			return fields
+		}
+	}
+
+	if runtime.GOOS == "windows" {
+		return []string{"notepad"}
+	}
+
+	return []string{"vi"}
+}
@@ -0,0 +1,168 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// fileName is the name of the config file in the config directory.
+const fileName = "config.json"
+
+// envPrefix is the prefix of the environment variables overriding settings.
+const envPrefix = "GOSW_"
+
+// Source is where the value of a setting comes from.
+type Source string
+
+const (
+	SourceFlag    Source = "flag"
+	SourceEnv     Source = "env"
+	SourceFile    Source = "file"
+	SourceDefault Source = "default"
+)
+
+// Overrides reports whether a setting from s takes precedence over a setting
+// from t.
+func (s Source) Overrides(t Source) bool {
+	return s.rank() > t.rank()
+}
+
+func (s Source) rank() int {
+	switch s {
+	case SourceFlag:
+		return 3
+	case SourceEnv:
+		return 2
+	case SourceFile:
+		return 1
+	}
+
+	return 0
+}
+
+// File is the content of the config file, which maps the names of the global
+// flags to their values.
+type File map[string]string
+
+// Path returns the path of the config file in confDir.
+func Path(confDir string) string {
+	return filepath.Join(confDir, fileName)
+}
+
+// Load reads the config file in confDir. An empty config is returned if the
+// file does not exist.
+func Load(confDir string) (File, error) {
+	f := make(File)
+
+	data, err := os.ReadFile(Path(confDir))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return f, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", fileName, err)
+	}
+
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", fileName, err)
+	}
+
+	return f, nil
+}
+
+// Save writes the config file in confDir.
+func (f File) Save(confDir string) error {
+	if err := os.MkdirAll(confDir, 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", fileName, err)
+	}
+
+	if err := os.WriteFile(Path(confDir), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", fileName, err)
+	}
+
+	return nil
+}
+
+// EnvVar returns the name of the environment variable overriding the setting
+// of the flag, e.g. GOSW_CACHE_DIR for --cache-dir.
+func EnvVar(name string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// Lookup returns the effective value of the setting of the flag and its
+// source. The flag takes precedence over the environment variable, which
+// takes precedence over the config file, which takes precedence over the
+// default.
+func Lookup(flag *pflag.Flag, f File) (string, Source) {
+	if flag.Changed {
+		return flagValue(flag), SourceFlag
+	}
+	if value, ok := os.LookupEnv(EnvVar(flag.Name)); ok {
+		return value, SourceEnv
+	}
+	if value, ok := f[flag.Name]; ok {
+		return value, SourceFile
+	}
+
+	return flagValue(flag), SourceDefault
+}
+
+func flagValue(flag *pflag.Flag) string {
+	if v, ok := flag.Value.(pflag.SliceValue); ok {
+		return strings.Join(v.GetSlice(), ",")
+	}
+
+	return flag.Value.String()
+}
+
+// Apply sets the flags that are not specified on the command line from the
+// environment variables and the config file.
+func Apply(flags *pflag.FlagSet, f File) error {
+	if err := Validate(flags, f); err != nil {
+		return err
+	}
+
+	var err error
+	flags.VisitAll(func(flag *pflag.Flag) {
+		if err != nil || flag.Changed {
+			return
+		}
+
+		value, source := Lookup(flag, f)
+		if source == SourceDefault {
+			return
+		}
+
+		if e := flags.Set(flag.Name, value); e != nil {
+			if source == SourceEnv {
+				err = fmt.Errorf("invalid value of %s: %w", EnvVar(flag.Name), e)
+			} else {
+				err = fmt.Errorf("invalid value of %s in %s: %w", flag.Name, fileName, e)
+			}
+		}
+	})
+
+	return err
+}
+
+// Validate returns an error if the config file has a setting that is not a
+// global flag.
+func Validate(flags *pflag.FlagSet, f File) error {
+	for name := range f {
+		if flags.Lookup(name) == nil {
+			return fmt.Errorf("unknown setting in %s: %s", fileName, name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,191 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func newTestFlags(t *testing.T, args ...string) *pflag.FlagSet {
+	t.Helper()
+
+	flags := pflag.NewFlagSet("gosw", pflag.ContinueOnError)
+	flags.String("cache-dir", "/default", "")
+	flags.Bool("offline", false, "")
+	flags.StringSlice("mirror", nil, "")
+	if err := flags.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+
+	return flags
+}
+
+// setEnv sets the environment variable for the test, or unsets it if value is
+// empty.
+func setEnv(t *testing.T, name, value string) {
+	t.Helper()
+
+	t.Setenv(name, value)
+	if value == "" {
+		os.Unsetenv(name)
+	}
+}
+
+var lookupTests = map[string]struct {
+	args   []string
+	env    string // the value of GOSW_CACHE_DIR, unset if empty
+	file   File
+	value  string
+	source Source
+}{
+	"default": {
+		value:  "/default",
+		source: SourceDefault,
+	},
+	"file": {
+		file:   File{"cache-dir": "/file"},
+		value:  "/file",
+		source: SourceFile,
+	},
+	"env": {
+		env:    "/env",
+		value:  "/env",
+		source: SourceEnv,
+	},
+	"env over file": {
+		env:    "/env",
+		file:   File{"cache-dir": "/file"},
+		value:  "/env",
+		source: SourceEnv,
+	},
+	"flag": {
+		args:   []string{"--cache-dir", "/flag"},
+		value:  "/flag",
+		source: SourceFlag,
+	},
+	"flag over file": {
+		args:   []string{"--cache-dir", "/flag"},
+		file:   File{"cache-dir": "/file"},
+		value:  "/flag",
+		source: SourceFlag,
+	},
+	"flag over env": {
+		args:   []string{"--cache-dir", "/flag"},
+		env:    "/env",
+		value:  "/flag",
+		source: SourceFlag,
+	},
+	"flag over env and file": {
+		args:   []string{"--cache-dir", "/flag"},
+		env:    "/env",
+		file:   File{"cache-dir": "/file"},
+		value:  "/flag",
+		source: SourceFlag,
+	},
+	"flag set to the default": {
+		args:   []string{"--cache-dir", "/default"},
+		env:    "/env",
+		value:  "/default",
+		source: SourceFlag,
+	},
+	"other setting in file": {
+		file:   File{"offline": "true"},
+		value:  "/default",
+		source: SourceDefault,
+	},
+}
+
+func Test_Lookup(t *testing.T) {
+	for name, tt := range lookupTests {
+		t.Run(name, func(t *testing.T) {
+			setEnv(t, EnvVar("cache-dir"), tt.env)
+			flags := newTestFlags(t, tt.args...)
+
+			value, source := Lookup(flags.Lookup("cache-dir"), tt.file)
+			if value != tt.value || source != tt.source {
+				t.Errorf("Lookup: got %q, %v, want %q, %v", value, source, tt.value, tt.source)
+			}
+
+			if err := Apply(flags, tt.file); err != nil {
+				t.Fatalf("Apply: %v", err)
+			}
+			if got, _ := flags.GetString("cache-dir"); got != tt.value {
+				t.Errorf("Apply: got %q, want %q", got, tt.value)
+			}
+		})
+	}
+}
+
+func Test_Lookup_slice(t *testing.T) {
+	setEnv(t, EnvVar("mirror"), "")
+	flags := newTestFlags(t, "--mirror", "https://a.example", "--mirror", "https://b.example")
+
+	value, source := Lookup(flags.Lookup("mirror"), File{"mirror": "https://c.example"})
+	if want := "https://a.example,https://b.example"; value != want || source != SourceFlag {
+		t.Errorf("Lookup: got %q, %v, want %q, %v", value, source, want, SourceFlag)
+	}
+}
+
+var applyErrorTests = map[string]struct {
+	env  string // the value of GOSW_OFFLINE, unset if empty
+	file File
+}{
+	"bad env": {
+		env: "maybe",
+	},
+	"bad env over good file": {
+		env:  "maybe",
+		file: File{"offline": "true"},
+	},
+	"bad file": {
+		file: File{"offline": "maybe"},
+	},
+	"unknown setting in file": {
+		file: File{"no-such-flag": "true"},
+	},
+}
+
+func Test_Apply_error(t *testing.T) {
+	for name, tt := range applyErrorTests {
+		t.Run(name, func(t *testing.T) {
+			setEnv(t, EnvVar("offline"), tt.env)
+			flags := newTestFlags(t)
+
+			if err := Apply(flags, tt.file); err == nil {
+				t.Error("Apply: got nil, want error")
+			}
+		})
+	}
+}
+
+func Test_Apply_badValueOverridden(t *testing.T) {
+	// the bad values are not used because the flag takes precedence
+	t.Setenv(EnvVar("offline"), "maybe")
+	flags := newTestFlags(t, "--offline")
+
+	if err := Apply(flags, File{"offline": "maybe"}); err != nil {
+		t.Errorf("Apply: %v", err)
+	}
+}
+
+var overridesTests = map[string]struct {
+	s, t Source
+	want bool
+}{
+	"flag over env":     {s: SourceFlag, t: SourceEnv, want: true},
+	"env over file":     {s: SourceEnv, t: SourceFile, want: true},
+	"file over default": {s: SourceFile, t: SourceDefault, want: true},
+	"file under env":    {s: SourceFile, t: SourceEnv, want: false},
+	"same source":       {s: SourceEnv, t: SourceEnv, want: false},
+}
+
+func Test_Source_Overrides(t *testing.T) {
+	for name, tt := range overridesTests {
+		t.Run(name, func(t *testing.T) {
+			if got := tt.s.Overrides(tt.t); got != tt.want {
+				t.Errorf("%v.Overrides(%v): got %v, want %v", tt.s, tt.t, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+// Package current provides the current command for the gosw CLI.
+package current
+
+import (
+	"fmt"
+
+	"github.com/kechako/gosw/env"
+	"github.com/spf13/cobra"
+)
+
+func Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "current",
+		Short: "Show the current Go version",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			e := env.FromContext(cmd.Context())
+
+			v, err := e.CurrentVersion()
+			if err != nil {
+				return err
+			}
+
+			supported, err := e.IsSupported(v)
+			if err != nil {
+				return err
+			}
+
+			if supported {
+				fmt.Println(v)
+			} else {
+				fmt.Printf("%s (unsupported)\n", v)
+			}
+
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,49 @@
+// Package history provides the history command for the gosw CLI.
+package history
+
+import (
+	"os"
+
+	"github.com/kechako/gosw/env"
+	"github.com/kechako/table"
+	"github.com/spf13/cobra"
+)
+
+func Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history [flags]",
+		Short: "Show the recent switches of the current Go version",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			e := env.FromContext(cmd.Context())
+
+			entries, err := e.History()
+			if err != nil {
+				return err
+			}
+
+			limit, _ := cmd.Flags().GetInt("limit")
+			if limit > 0 && len(entries) > limit {
+				entries = entries[:limit]
+			}
+
+			t := table.New(
+				&table.Column{Title: "Time", Alignment: table.AlignLeft},
+				&table.Column{Title: "Version", Alignment: table.AlignLeft},
+			)
+			for _, entry := range entries {
+				t.AddRow(
+					table.String(entry.Time.Local().Format("2006-01-02 15:04:05")),
+					table.String(entry.Version.String()),
+				)
+			}
+			t.Format(os.Stdout)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().IntP("limit", "n", 20, "Show at most this number of switches (0 for all)")
+
+	return cmd
+}
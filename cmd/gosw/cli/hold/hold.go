@@ -0,0 +1,57 @@
+// Package hold provides the hold command for the gosw CLI.
+package hold
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kechako/gosw/env"
+	"github.com/spf13/cobra"
+)
+
+func Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "hold [<version>]",
+		Short: "Hold a Go version against uninstallation and upgrade, or list held versions",
+		Args:  cobra.RangeArgs(0, 1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+
+			e := env.FromContext(cmd.Context())
+			versions := e.InstalledVersions()
+
+			completions := make([]cobra.Completion, 0, len(versions))
+			for _, version := range versions {
+				if strings.HasPrefix(version.String(), toComplete) {
+					completions = append(completions, cobra.Completion(version.String()))
+				}
+			}
+			return completions, cobra.ShellCompDirectiveNoSpace
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			e := env.FromContext(cmd.Context())
+
+			if len(args) == 0 {
+				versions, err := e.HeldVersions()
+				if err != nil {
+					return err
+				}
+				for _, v := range versions {
+					fmt.Println(v)
+				}
+				return nil
+			}
+
+			v, err := e.ResolveVersion(args[0])
+			if err != nil {
+				return err
+			}
+
+			return e.Hold(v)
+		},
+	}
+
+	return cmd
+}
@@ -4,10 +4,11 @@ package install
 import (
 	"errors"
 	"fmt"
-	"math"
 	"os"
+	"runtime"
 	"strings"
 
+	"github.com/kechako/gosw/cmd/gosw/cli/cliformat"
 	"github.com/kechako/gosw/env"
 	"github.com/kechako/table"
 	"github.com/spf13/cobra"
@@ -63,9 +64,25 @@ func Command() *cobra.Command {
 			list, _ := cmd.Flags().GetBool("list")
 			listAll, _ := cmd.Flags().GetBool("list-all")
 			if !list && !listAll {
-				v, err := env.ParseVersion(args[0])
+				v, err := e.ResolveVersion(args[0])
 				if err != nil {
-					return errors.New("version syntax is not valid")
+					return err
+				}
+				if cmd.Flags().Changed("arch") {
+					arch, _ := cmd.Flags().GetString("arch")
+					v = v.ForPlatform(runtime.GOOS, arch)
+				}
+
+				supported, err := e.IsSupported(v)
+				if err != nil {
+					return err
+				}
+				if !supported {
+					strict, _ := cmd.Flags().GetBool("strict")
+					if strict {
+						return fmt.Errorf("minor version %d.%d is no longer supported", v.Major, v.Minor)
+					}
+					fmt.Fprintf(os.Stderr, "warning: minor version %d.%d is no longer supported\n", v.Major, v.Minor)
 				}
 
 				if err := e.Install(v); err != nil {
@@ -75,13 +92,29 @@ func Command() *cobra.Command {
 				return nil
 			}
 
+			filter := releaseFilter(cmd)
+
 			var releases []*env.Release
 			var err error
 			if listAll {
-				releases, err = e.Releases()
+				releases, err = e.FilterReleases(filter)
 			} else if list {
-				releases, err = e.RecentReleases()
+				opts, optsErr := recentOptions(cmd)
+				if optsErr != nil {
+					return optsErr
+				}
+				opts.Filter = filter
+				releases, err = e.RecentReleases(opts)
+			}
+			if err != nil {
+				return err
 			}
+
+			if e.UsingSnapshot() {
+				fmt.Fprintln(os.Stderr, "warning: the list of available versions is bundled with gosw and may be stale, run 'gosw update' to get the latest one")
+			}
+
+			window, err := e.SupportWindow()
 			if err != nil {
 				return err
 			}
@@ -92,6 +125,10 @@ func Command() *cobra.Command {
 				t := table.New(
 					&table.Column{Title: "Version", Alignment: table.AlignLeft},
 					&table.Column{Title: "Stable", Alignment: table.AlignCenter},
+					&table.Column{Title: "Supported", Alignment: table.AlignCenter},
+					&table.Column{Title: "OS", Alignment: table.AlignLeft},
+					&table.Column{Title: "Arch", Alignment: table.AlignLeft},
+					&table.Column{Title: "Kind", Alignment: table.AlignLeft},
 					&table.Column{Title: "Filename", Alignment: table.AlignLeft},
 					&table.Column{Title: "Size", Alignment: table.AlignRight},
 					&table.Column{Title: "Checksum SHA256", Alignment: table.AlignLeft},
@@ -100,15 +137,29 @@ func Command() *cobra.Command {
 					t.AddRow(
 						table.String(r.Version.String()),
 						table.Bool(r.Stable),
+						table.Bool(window.Supports(r.Version)),
+						table.String(orDash(r.OS)),
+						table.String(orDash(r.Arch)),
+						table.String(r.Kind),
 						table.String(r.Filename),
-						table.String(formatBytes(r.Size)),
-						table.String(r.ChecksumSHA256),
+						table.String(sizeOrDash(r.Size)),
+						table.String(orDash(r.ChecksumSHA256)),
 					)
 				}
 				t.Format(os.Stdout)
 			} else {
+				var last *env.Version
 				for _, r := range releases {
-					fmt.Println(r.Version)
+					// print each version once even if multiple files are selected
+					if last != nil && env.EqualVersion(last, r.Version) {
+						continue
+					}
+					if window.Supports(r.Version) {
+						fmt.Println(r.Version)
+					} else {
+						fmt.Printf("%s (unsupported)\n", r.Version)
+					}
+					last = r.Version
 				}
 			}
 
@@ -119,57 +170,72 @@ func Command() *cobra.Command {
 	cmd.Flags().BoolP("list", "l", false, "List recent available versions")
 	cmd.Flags().BoolP("list-all", "L", false, "List all available versions")
 	cmd.Flags().BoolP("verbose", "v", false, "Show detailed information about versions")
+	cmd.Flags().String("os", runtime.GOOS, "List versions for the OS ('any' for all)")
+	cmd.Flags().String("arch", runtime.GOARCH, "Install the version for the architecture, or list versions for the architecture ('any' for all)")
+	cmd.Flags().Bool("strict", false, "Refuse a version that is no longer supported")
+	cmd.Flags().Int("minors", 0, "List the number of the most recent minor versions with --list (0 for the supported ones)")
+	cmd.Flags().Int("patches", 2, "List the number of the most recent patch versions of each minor version with --list (0 for all)")
+	cmd.Flags().String("since", "", "List every minor version since the specified one with --list, e.g. 1.20")
+	cmd.Flags().Bool("stable-only", false, "List no prerelease versions with --list")
+	cmd.Flags().Bool("prerelease", false, "List prerelease versions of every listed minor version with --list")
+	cmd.MarkFlagsMutuallyExclusive("stable-only", "prerelease")
+	cmd.MarkFlagsMutuallyExclusive("minors", "since")
+	cmd.Flags().String("kind", "archive", "List versions of the kind of files: archive, installer or source ('any' for all)")
 
 	return cmd
 }
 
-func formatBytes(value int64) string {
-	bytes := float64(value)
-
-	// snprintf below uses %4.2f, so 1023.99 MiB should be shown as 1.00 GiB
-	bytesAbs := math.Abs(bytes) / 1023.995 * 1024
-
-	const kib = uint64(1024)
-	const mib = uint64(1024 * kib)
-	const gib = uint64(1024 * mib)
-	const tib = uint64(1024 * gib)
-	const pib = uint64(1024 * tib)
-	const eib = uint64(1024 * pib)
-
-	var divisor uint64
-	var unit string
-
-	if bytesAbs >= float64(eib) {
-		divisor = eib
-		unit = "EiB"
-	} else if bytesAbs >= float64(pib) {
-		divisor = pib
-		unit = "PiB"
-	} else if bytesAbs >= float64(tib) {
-		divisor = tib
-		unit = "TiB"
-	} else if bytesAbs >= float64(gib) {
-		divisor = gib
-		unit = "GiB"
-	} else if bytesAbs >= float64(mib) {
-		divisor = mib
-		unit = "MiB"
-	} else if bytesAbs >= float64(kib) {
-		divisor = kib
-		unit = "KiB"
-	} else {
-		divisor = 1
-		unit = "bytes"
+func recentOptions(cmd *cobra.Command) (*env.RecentOptions, error) {
+	opts := env.DefaultRecentOptions()
+	opts.Minors, _ = cmd.Flags().GetInt("minors")
+	opts.Patches, _ = cmd.Flags().GetInt("patches")
+	opts.StableOnly, _ = cmd.Flags().GetBool("stable-only")
+	opts.AllPrereleases, _ = cmd.Flags().GetBool("prerelease")
+
+	since, _ := cmd.Flags().GetString("since")
+	if since != "" {
+		v, err := env.ParseVersion(since)
+		if err != nil {
+			return nil, errors.New("version syntax of --since is not valid")
+		}
+		opts.Since = v
+	}
+
+	return opts, nil
+}
+
+func releaseFilter(cmd *cobra.Command) env.ReleaseFilter {
+	osName, _ := cmd.Flags().GetString("os")
+	arch, _ := cmd.Flags().GetString("arch")
+	kind, _ := cmd.Flags().GetString("kind")
+
+	return env.ReleaseFilter{
+		OS:   anyToEmpty(osName),
+		Arch: anyToEmpty(arch),
+		Kind: anyToEmpty(kind),
 	}
+}
 
-	if divisor == 1 {
-		return fmt.Sprintf("%4d %s", int(bytes), unit)
-	} else {
-		v := bytes / float64(divisor)
-		if math.Abs(v) >= 100000.0 {
-			return fmt.Sprintf("%4.2e %s", v, unit)
-		} else {
-			return fmt.Sprintf("%4.2f %s", v, unit)
-		}
+func anyToEmpty(s string) string {
+	if s == "any" {
+		return ""
+	}
+
+	return s
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+
+	return s
+}
+
+func sizeOrDash(size int64) string {
+	if size == 0 {
+		return "-"
 	}
+
+	return cliformat.Bytes(size)
 }
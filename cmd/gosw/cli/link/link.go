@@ -0,0 +1,178 @@
+// Package link provides the link command for the gosw CLI.
+package link
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kechako/gosw/env"
+	"github.com/spf13/cobra"
+)
+
+func Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "link",
+		Short: "Manage named links to Go versions",
+	}
+
+	cmd.AddCommand(
+		listCommand(),
+		setCommand(),
+		removeCommand(),
+	)
+
+	return cmd
+}
+
+func listCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List named links",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			e := env.FromContext(cmd.Context())
+
+			links, err := e.Links()
+			if err != nil {
+				return err
+			}
+
+			for _, link := range links {
+				fmt.Println(formatLink(e, link))
+			}
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func formatLink(e *env.Env, link *env.Link) string {
+	var target string
+	switch {
+	case link.Version == nil:
+		target = "(none)"
+	case !e.HasVersion(link.Version):
+		target = link.Version.String() + " (not installed)"
+	default:
+		target = link.Version.String()
+	}
+
+	if link.Channel != "" {
+		return fmt.Sprintf("%s -> %s [%s]", link.Name, target, link.Channel)
+	}
+
+	return fmt.Sprintf("%s -> %s", link.Name, target)
+}
+
+func setCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set <name> [<version>]",
+		Short: "Point a named link to a Go version or a release channel",
+		Long: `Point a named link to a Go version or a release channel.
+
+A link following the stable channel points to the newest installed stable
+version, and a link following the beta channel points to the newest installed
+prerelease (beta or rc). They are updated on install, uninstall and update.`,
+		Args: cobra.RangeArgs(1, 2),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+			e := env.FromContext(cmd.Context())
+
+			var completions []cobra.Completion
+			switch len(args) {
+			case 0:
+				links, _ := e.Links()
+				for _, link := range links {
+					if strings.HasPrefix(link.Name, toComplete) {
+						completions = append(completions, cobra.Completion(link.Name))
+					}
+				}
+			case 1:
+				for _, version := range e.InstalledVersions() {
+					if strings.HasPrefix(version.String(), toComplete) {
+						completions = append(completions, cobra.Completion(version.String()))
+					}
+				}
+				aliases, _ := e.Aliases()
+				for _, alias := range aliases {
+					if strings.HasPrefix(alias.Name, toComplete) {
+						completions = append(completions, cobra.Completion(alias.Name))
+					}
+				}
+			}
+			return completions, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			e := env.FromContext(cmd.Context())
+			name := args[0]
+
+			if cmd.Flags().Changed("channel") {
+				if len(args) > 1 {
+					return fmt.Errorf("either a version or --channel can be specified")
+				}
+
+				s, _ := cmd.Flags().GetString("channel")
+				ch, err := env.ParseChannel(s)
+				if err != nil {
+					return err
+				}
+
+				v, err := e.FollowChannel(name, ch)
+				if err != nil {
+					return err
+				}
+
+				fmt.Println(formatLink(e, &env.Link{Name: name, Version: v, Channel: ch}))
+				return nil
+			}
+
+			if len(args) < 2 {
+				return fmt.Errorf("a version or --channel must be specified")
+			}
+
+			v, err := e.ResolveVersion(args[1])
+			if err != nil {
+				return err
+			}
+
+			return e.SetLink(name, v)
+		},
+	}
+
+	cmd.Flags().String("channel", "", "Follow a release channel (stable or beta)")
+
+	return cmd
+}
+
+func removeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rm <name>",
+		Short: "Remove a named link",
+		Args:  cobra.ExactArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+
+			e := env.FromContext(cmd.Context())
+			links, err := e.Links()
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveError
+			}
+
+			completions := make([]cobra.Completion, 0, len(links))
+			for _, link := range links {
+				completions = append(completions, cobra.Completion(link.Name))
+			}
+			return completions, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			e := env.FromContext(cmd.Context())
+
+			return e.RemoveLink(args[0])
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,104 @@
+// Package migrate provides the migrate command for the gosw CLI.
+package migrate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/kechako/gosw/cmd/gosw/cli/config"
+	"github.com/kechako/gosw/env"
+	"github.com/spf13/cobra"
+)
+
+func Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate [flags]",
+		Short: "Move the installed Go versions to another root",
+		Long: `Move the installed Go versions to another root.
+
+The versions in the root are moved to the directory specified by --to, or to
+the per-user root in $XDG_DATA_HOME/gosw by default, and the links are
+recreated there. If anything fails, the old root is left untouched.
+
+The new root is saved to the config file, so that subsequent runs use it.`,
+		Args: cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			e := env.FromContext(cmd.Context())
+
+			to, _ := cmd.Flags().GetString("to")
+			if to == "" {
+				root, err := env.DefaultUserEnvRoot()
+				if err != nil {
+					return err
+				}
+				to = root
+			}
+			to, err := filepath.Abs(to)
+			if err != nil {
+				return err
+			}
+
+			var opts []env.MigrateOption
+			if copy, _ := cmd.Flags().GetBool("copy"); copy {
+				opts = append(opts, env.Copy())
+			}
+
+			// the new root is saved first, so that a failure to save it never
+			// leaves the config pointing to the emptied old root
+			restore, err := saveRoot(to)
+			if err != nil {
+				return err
+			}
+
+			if err := e.Migrate(to, opts...); err != nil {
+				if rerr := restore(); rerr != nil {
+					fmt.Fprintf(os.Stderr, "warning: failed to restore the root in the config file: %v\n", rerr)
+				}
+				return err
+			}
+			fmt.Printf("Migrated to %s\n", to)
+
+			if _, ok := os.LookupEnv(config.EnvVar("root")); ok {
+				fmt.Fprintf(os.Stderr, "warning: %s overrides the root in the config file\n", config.EnvVar("root"))
+			}
+			fmt.Println("Run 'gosw path' to update PATH.")
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String("to", "", "Move the versions to the directory (the per-user root if empty)")
+	cmd.Flags().Bool("copy", false, "Copy the versions and leave the old root as it is")
+
+	return cmd
+}
+
+// saveRoot saves the root to the config file, and returns the function to
+// restore the previous root.
+func saveRoot(root string) (restore func() error, err error) {
+	confDir, err := env.DefaultConfigDir()
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := config.Load(confDir)
+	if err != nil {
+		return nil, err
+	}
+	prev, ok := f["root"]
+	f["root"] = root
+
+	if err := f.Save(confDir); err != nil {
+		return nil, err
+	}
+
+	return func() error {
+		if ok {
+			f["root"] = prev
+		} else {
+			delete(f, "root")
+		}
+		return f.Save(confDir)
+	}, nil
+}
@@ -0,0 +1,90 @@
+// Package outdated provides the outdated command for the gosw CLI.
+package outdated
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kechako/gosw/cmd/gosw/cli/clierrors"
+	"github.com/kechako/gosw/env"
+	"github.com/kechako/table"
+	"github.com/spf13/cobra"
+)
+
+// exitCodeOutdated is the exit code when the current version is outdated.
+const exitCodeOutdated = 2
+
+func Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "outdated",
+		Short: "Report installed Go versions that have newer patches or are no longer supported",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			e := env.FromContext(cmd.Context())
+
+			statuses, err := e.VersionStatuses()
+			if err != nil {
+				return err
+			}
+
+			if e.UsingSnapshot() {
+				fmt.Fprintln(os.Stderr, "warning: the list of available versions is bundled with gosw and may be stale, run 'gosw update' to get the latest one")
+			}
+
+			t := table.New(
+				&table.Column{Title: " ", Alignment: table.AlignLeft},
+				&table.Column{Title: "Version", Alignment: table.AlignLeft},
+				&table.Column{Title: "Latest", Alignment: table.AlignLeft},
+				&table.Column{Title: "Status", Alignment: table.AlignLeft},
+			)
+
+			var current *env.VersionStatus
+			for _, s := range statuses {
+				mark := " "
+				if s.Current {
+					mark = "*"
+					current = s
+				}
+				latest := "-"
+				if s.Latest != nil {
+					latest = s.Latest.String()
+				}
+				t.AddRow(
+					table.String(mark),
+					table.String(s.Version.String()),
+					table.String(latest),
+					table.String(statusText(s)),
+				)
+			}
+			t.Format(os.Stdout)
+
+			if current == nil || (!current.Outdated() && current.Supported) {
+				return nil
+			}
+
+			if current.Outdated() {
+				fmt.Fprintf(os.Stderr, "The current version %s is outdated, the latest patch version is %s.\n", current.Version, current.Latest)
+			}
+			if !current.Supported {
+				fmt.Fprintf(os.Stderr, "The current version %s is no longer supported.\n", current.Version)
+			}
+
+			return clierrors.Exit(nil, exitCodeOutdated)
+		},
+	}
+
+	return cmd
+}
+
+func statusText(s *env.VersionStatus) string {
+	switch {
+	case !s.Supported && s.Outdated():
+		return "unsupported, update available"
+	case !s.Supported:
+		return "unsupported"
+	case s.Outdated():
+		return "update available"
+	default:
+		return "up to date"
+	}
+}
@@ -0,0 +1,66 @@
+// Package path provides the path command for the gosw CLI.
+package path
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kechako/gosw/env"
+	"github.com/spf13/cobra"
+)
+
+func Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "path [flags]",
+		Short: "Print the line to add the Go commands to PATH",
+		Long: `Print the line to add the Go commands to PATH.
+
+Add the line to your shell profile, e.g. ~/.profile, or evaluate it:
+
+  eval "$(gosw path)"`,
+		Args: cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			e := env.FromContext(cmd.Context())
+
+			dirs := []string{e.LinkBinDir()}
+			if dir := e.CommandDir(); dir != "" {
+				dirs = append(dirs, dir)
+			}
+
+			shell, _ := cmd.Flags().GetString("shell")
+			switch shell {
+			case "fish":
+				fmt.Printf("fish_add_path --prepend %s\n", strings.Join(quote(dirs), " "))
+			case "sh", "bash", "zsh", "ksh", "dash":
+				fmt.Printf("export PATH=\"%s:$PATH\"\n", strings.Join(dirs, ":"))
+			default:
+				return fmt.Errorf("unsupported shell: %s", shell)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String("shell", defaultShell(), "Print the line for the shell")
+
+	return cmd
+}
+
+func defaultShell() string {
+	if shell := filepath.Base(os.Getenv("SHELL")); shell == "fish" {
+		return shell
+	}
+
+	return "sh"
+}
+
+func quote(dirs []string) []string {
+	quoted := make([]string, len(dirs))
+	for i, dir := range dirs {
+		quoted[i] = "'" + strings.ReplaceAll(dir, "'", `\'`) + "'"
+	}
+
+	return quoted
+}
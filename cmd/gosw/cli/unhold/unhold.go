@@ -0,0 +1,48 @@
+// Package unhold provides the unhold command for the gosw CLI.
+package unhold
+
+import (
+	"strings"
+
+	"github.com/kechako/gosw/env"
+	"github.com/spf13/cobra"
+)
+
+func Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "unhold <version>",
+		Short: "Release a held Go version",
+		Args:  cobra.ExactArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+
+			e := env.FromContext(cmd.Context())
+			versions, err := e.HeldVersions()
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveError
+			}
+
+			completions := make([]cobra.Completion, 0, len(versions))
+			for _, version := range versions {
+				if strings.HasPrefix(version.String(), toComplete) {
+					completions = append(completions, cobra.Completion(version.String()))
+				}
+			}
+			return completions, cobra.ShellCompDirectiveNoSpace
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			e := env.FromContext(cmd.Context())
+
+			v, err := e.ResolveVersion(args[0])
+			if err != nil {
+				return err
+			}
+
+			return e.Unhold(v)
+		},
+	}
+
+	return cmd
+}
@@ -2,49 +2,236 @@
 package uninstall
 
 import (
+	"bufio"
 	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"slices"
 	"strings"
 
+	"github.com/kechako/gosw/cmd/gosw/cli/cliformat"
 	"github.com/kechako/gosw/env"
+	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 )
 
 func Command() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "uninstall [flags] <version>",
-		Short: "Uninstall a specific Go version",
-		Args:  cobra.ExactArgs(1),
-		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
-			if len(args) > 0 {
-				return nil, cobra.ShellCompDirectiveNoFileComp
-			}
+		Use:   "uninstall [flags] [<version>...]",
+		Short: "Uninstall Go versions",
+		Long: `Uninstall Go versions.
 
+A version without a patch such as 1.20 selects every installed version of the
+minor line. Held versions and the current version are skipped unless --force
+is specified.
+
+Unless a single version is specified, the selected versions are confirmed
+before uninstallation, which requires --yes if the input is not a terminal.`,
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
 			e := env.FromContext(cmd.Context())
 			versions := e.InstalledVersions()
 
 			completions := make([]cobra.Completion, 0, len(versions))
 			for _, version := range versions {
-				if strings.HasPrefix(version.String(), toComplete) {
+				if strings.HasPrefix(version.String(), toComplete) && !slices.Contains(args, version.String()) {
 					completions = append(completions, cobra.Completion(version.String()))
 				}
 			}
+			aliases, _ := e.Aliases()
+			for _, alias := range aliases {
+				if strings.HasPrefix(alias.Name, toComplete) && !slices.Contains(args, alias.Name) {
+					completions = append(completions, cobra.Completion(alias.Name))
+				}
+			}
 			return completions, cobra.ShellCompDirectiveNoSpace
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			e := env.FromContext(cmd.Context())
 
-			v, err := env.ParseVersion(args[0])
-			if err != nil {
-				return errors.New("version syntax is not valid")
+			allExceptCurrent, _ := cmd.Flags().GetBool("all-except-current")
+			prereleases, _ := cmd.Flags().GetBool("prereleases")
+			force, _ := cmd.Flags().GetBool("force")
+			purge, _ := cmd.Flags().GetBool("purge")
+			yes, _ := cmd.Flags().GetBool("yes")
+
+			if len(args) == 0 && !allExceptCurrent && !prereleases {
+				return errors.New("specify versions, --all-except-current or --prereleases")
+			}
+
+			current, _ := e.CurrentVersion()
+
+			var selected []*env.Version
+			add := func(v *env.Version) {
+				if !slices.ContainsFunc(selected, func(s *env.Version) bool { return s.String() == v.String() }) {
+					selected = append(selected, v)
+				}
+			}
+
+			single := len(args) == 1 && !allExceptCurrent && !prereleases
+			for _, arg := range args {
+				v, err := e.ResolveVersion(arg)
+				if err != nil {
+					return err
+				}
+				if cmd.Flags().Changed("arch") {
+					arch, _ := cmd.Flags().GetString("arch")
+					v = v.ForPlatform(runtime.GOOS, arch)
+				}
+
+				matched, err := e.MatchVersions(v)
+				if err != nil {
+					return err
+				}
+				if len(matched) > 1 {
+					single = false
+				}
+				for _, m := range matched {
+					add(m)
+				}
+			}
+
+			for _, v := range e.InstalledVersions() {
+				if allExceptCurrent && (current == nil || v.String() != current.String()) {
+					add(v)
+				}
+				if prereleases && (v.Type == env.Beta || v.Type == env.RC) {
+					add(v)
+				}
+			}
+
+			var targets []*env.Version
+			var skipped []string
+			for _, v := range selected {
+				if !force {
+					held, err := e.IsHeld(v)
+					if err != nil {
+						return err
+					}
+					if held {
+						skipped = append(skipped, fmt.Sprintf("%s is held", v))
+						continue
+					}
+					if current != nil && v.String() == current.String() {
+						skipped = append(skipped, fmt.Sprintf("%s is the current version", v))
+						continue
+					}
+				}
+				targets = append(targets, v)
+			}
+
+			for _, s := range skipped {
+				fmt.Fprintf(os.Stderr, "Skip: %s, use --force to uninstall it\n", s)
+			}
+			if len(targets) == 0 {
+				if len(skipped) > 0 {
+					return errors.New("no version to uninstall")
+				}
+				fmt.Println("No version to uninstall.")
+				return nil
+			}
+
+			if !single && !yes {
+				fmt.Println("The following versions will be uninstalled:")
+				for _, v := range targets {
+					fmt.Printf("  %s\n", v)
+				}
+				ok, err := confirm(fmt.Sprintf("Uninstall %d versions?", len(targets)))
+				if err != nil {
+					return err
+				}
+				if !ok {
+					return errors.New("canceled")
+				}
 			}
 
-			if err := e.Uninstall(v); err != nil {
-				return err
+			var opts []env.RemoveOption
+			if force {
+				opts = append(opts, env.Force())
 			}
 
+			var reclaimed int64
+			for _, v := range targets {
+				size, err := e.InstalledSize(v)
+				if err != nil {
+					return err
+				}
+
+				if err := e.Uninstall(v, opts...); err != nil {
+					return err
+				}
+				reclaimed += size
+				fmt.Printf("Uninstalled %s\n", v)
+
+				if purge {
+					archives, err := e.RemoveCachedArchives(v, opts...)
+					if err != nil && !errors.Is(err, env.ErrNotCached) {
+						return err
+					}
+					for _, a := range archives {
+						reclaimed += a.Size
+						fmt.Printf("Removed %s\n", a.Name)
+					}
+				}
+			}
+
+			if len(targets) > 1 {
+				fmt.Printf("Uninstalled %d versions, reclaimed %s\n", len(targets), strings.TrimSpace(cliformat.Bytes(reclaimed)))
+			} else {
+				fmt.Printf("Reclaimed %s\n", strings.TrimSpace(cliformat.Bytes(reclaimed)))
+			}
+
+			printCurrentChange(e, current)
+
 			return nil
 		},
 	}
 
+	cmd.Flags().String("arch", runtime.GOARCH, "Uninstall the versions for the architecture")
+	cmd.Flags().Bool("all-except-current", false, "Uninstall all versions except the current version")
+	cmd.Flags().Bool("prereleases", false, "Uninstall all beta and rc versions")
+	cmd.Flags().Bool("purge", false, "Remove the cached archives of the versions too")
+	cmd.Flags().BoolP("yes", "y", false, "Uninstall without confirmation")
+	cmd.Flags().BoolP("force", "f", false, "Uninstall the versions even if they are held or current")
+
 	return cmd
 }
+
+// confirm asks the user for confirmation. It fails if the input is not a
+// terminal.
+func confirm(prompt string) (bool, error) {
+	if !isatty.IsTerminal(os.Stdin.Fd()) && !isatty.IsCygwinTerminal(os.Stdin.Fd()) {
+		return false, errors.New("input is not a terminal, use --yes to uninstall without confirmation")
+	}
+
+	fmt.Printf("%s [y/N] ", prompt)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, err
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// printCurrentChange prints the current version if it is changed from
+// previous by the uninstallation.
+func printCurrentChange(e *env.Env, previous *env.Version) {
+	if previous == nil {
+		return
+	}
+
+	current, err := e.CurrentVersion()
+	if err != nil {
+		fmt.Printf("No version is current now, run 'gosw use' to select one.\n")
+		return
+	}
+	if current.String() != previous.String() {
+		fmt.Printf("Now using %s\n", current)
+	}
+}
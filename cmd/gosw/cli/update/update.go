@@ -2,25 +2,112 @@
 package update
 
 import (
+	"fmt"
+	"os"
+
+	"github.com/kechako/gosw/cmd/gosw/cli/clierrors"
 	"github.com/kechako/gosw/env"
 	"github.com/spf13/cobra"
 )
 
+// exitCodeUpdatesAvailable is the exit code of --check when installed minor
+// lines have newer patch versions.
+const exitCodeUpdatesAvailable = 2
+
 func Command() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "update",
+		Use:   "update [flags]",
 		Short: "Update the list of available Go versions",
 		Args:  cobra.ExactArgs(0),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			e := env.FromContext(cmd.Context())
 
-			if err := e.UpdateDownloadList(); err != nil {
+			changes, err := e.UpdateDownloadList()
+			if err != nil {
+				return err
+			}
+
+			for _, w := range e.Warnings() {
+				fmt.Fprintf(os.Stderr, "warning: %v\n", w)
+			}
+
+			printChanges(e, changes)
+
+			links, err := e.UpdateChannelLinks()
+			if err != nil {
+				return err
+			}
+			for _, link := range links {
+				if link.Version != nil {
+					fmt.Printf("Link %s now points to %s\n", link.Name, link.Version)
+				} else {
+					fmt.Printf("Link %s is removed, no %s version is installed\n", link.Name, link.Channel)
+				}
+			}
+
+			if err := e.UpdateMinorLinks(); err != nil {
+				return err
+			}
+
+			if err := e.UpdateCommands(); err != nil {
+				return err
+			}
+
+			check, _ := cmd.Flags().GetBool("check")
+			if !check {
+				return nil
+			}
+
+			upgrades, err := e.Upgrades()
+			if err != nil {
 				return err
 			}
+			if len(upgrades) > 0 {
+				return clierrors.Exit(nil, exitCodeUpdatesAvailable)
+			}
 
 			return nil
 		},
 	}
 
+	cmd.Flags().Bool("check", false, fmt.Sprintf("Exit with status %d if installed minor lines have newer patch versions", exitCodeUpdatesAvailable))
+
 	return cmd
 }
+
+func printChanges(e *env.Env, changes *env.ReleaseChanges) {
+	if changes == nil {
+		fmt.Println("Downloaded the list of available versions.")
+		return
+	}
+
+	if changes.Empty() {
+		fmt.Println("The list of available versions is up to date.")
+		return
+	}
+
+	if releases := changes.NewStable(); len(releases) > 0 {
+		fmt.Println("New stable versions:")
+		for _, r := range releases {
+			if installed := e.InstalledLineUpdate(r); installed != nil {
+				fmt.Printf("  %s (update for the installed version %s)\n", r.Version, installed)
+			} else {
+				fmt.Printf("  %s\n", r.Version)
+			}
+		}
+	}
+
+	if releases := changes.NewPrereleases(); len(releases) > 0 {
+		fmt.Println("New prerelease versions:")
+		for _, r := range releases {
+			fmt.Printf("  %s\n", r.Version)
+		}
+	}
+
+	if len(changes.Removed) > 0 {
+		fmt.Println("Removed versions:")
+		for _, r := range changes.Removed {
+			fmt.Printf("  %s\n", r.Version)
+		}
+	}
+}
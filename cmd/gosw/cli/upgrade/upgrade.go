@@ -0,0 +1,91 @@
+// Package upgrade provides the upgrade command for the gosw CLI.
+package upgrade
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kechako/gosw/env"
+	"github.com/spf13/cobra"
+)
+
+func Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "upgrade [flags]",
+		Short: "Upgrade installed minor lines to their latest patch versions",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			e := env.FromContext(cmd.Context())
+
+			current, err := e.CurrentVersion()
+			if err != nil && !errors.Is(err, env.ErrNoCurrentVersion) {
+				return err
+			}
+
+			upgrades, err := e.Upgrades()
+			if err != nil {
+				return err
+			}
+
+			if len(upgrades) == 0 {
+				fmt.Println("All installed versions are up to date.")
+				return nil
+			}
+
+			removeOld, _ := cmd.Flags().GetBool("remove-old")
+			force, _ := cmd.Flags().GetBool("force")
+
+			// a held current version stays current even if a newer version
+			// of the line is not held
+			currentHeld := false
+			if current != nil {
+				currentHeld, err = e.IsHeld(current)
+				if err != nil {
+					return err
+				}
+			}
+
+			for _, u := range upgrades {
+				held, err := e.IsHeld(u.From)
+				if err != nil {
+					return err
+				}
+				if held && !force {
+					fmt.Printf("Skip %s, which is held\n", u.From)
+					continue
+				}
+
+				fmt.Printf("Upgrade %s to %s\n", u.From, u.To)
+
+				if err := e.Install(u.To); err != nil {
+					return err
+				}
+
+				if current != nil && env.EqualMinorVersion(current, u.From) && env.SamePlatform(current, u.From) {
+					if currentHeld && !force {
+						fmt.Printf("Keep using %s, which is held\n", current)
+					} else {
+						if err := e.Switch(u.To); err != nil {
+							return err
+						}
+						fmt.Printf("Switched to %s\n", u.To)
+					}
+				}
+
+				if removeOld {
+					if err := e.Uninstall(u.From, env.Force()); err != nil {
+						return err
+					}
+					fmt.Printf("Uninstalled %s\n", u.From)
+				}
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolP("remove-old", "r", false, "Uninstall the versions superseded by the upgrade")
+	cmd.Flags().BoolP("force", "f", false, "Upgrade held versions too, and switch from the held current version")
+
+	return cmd
+}
@@ -2,7 +2,9 @@
 package use
 
 import (
-	"errors"
+	"fmt"
+	"os"
+	"runtime"
 	"strings"
 
 	"github.com/kechako/gosw/env"
@@ -12,7 +14,7 @@ import (
 func Command() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "use [flags] <version>",
-		Short: "Use a specific Go version",
+		Short: "Use a specific Go version, or the previous version with -",
 		Args:  cobra.ExactArgs(1),
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
 			if len(args) > 0 {
@@ -28,14 +30,42 @@ func Command() *cobra.Command {
 					completions = append(completions, cobra.Completion(version.String()))
 				}
 			}
+			aliases, _ := e.Aliases()
+			for _, alias := range aliases {
+				if strings.HasPrefix(alias.Name, toComplete) {
+					completions = append(completions, cobra.Completion(alias.Name))
+				}
+			}
 			return completions, cobra.ShellCompDirectiveNoSpace
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			e := env.FromContext(cmd.Context())
 
-			v, err := env.ParseVersion(args[0])
+			var v *env.Version
+			var err error
+			if args[0] == "-" {
+				v, err = e.PreviousVersion()
+			} else {
+				v, err = e.ResolveVersion(args[0])
+			}
+			if err != nil {
+				return err
+			}
+			if args[0] != "-" && cmd.Flags().Changed("arch") {
+				arch, _ := cmd.Flags().GetString("arch")
+				v = v.ForPlatform(runtime.GOOS, arch)
+			}
+
+			supported, err := e.IsSupported(v)
 			if err != nil {
-				return errors.New("version syntax is not valid")
+				return err
+			}
+			if !supported {
+				strict, _ := cmd.Flags().GetBool("strict")
+				if strict {
+					return fmt.Errorf("minor version %d.%d is no longer supported", v.Major, v.Minor)
+				}
+				fmt.Fprintf(os.Stderr, "warning: minor version %d.%d is no longer supported\n", v.Major, v.Minor)
 			}
 
 			if err := e.Switch(v); err != nil {
@@ -46,5 +76,8 @@ func Command() *cobra.Command {
 		},
 	}
 
+	cmd.Flags().String("arch", runtime.GOARCH, "Use the version for the architecture")
+	cmd.Flags().Bool("strict", false, "Refuse a version that is no longer supported")
+
 	return cmd
 }
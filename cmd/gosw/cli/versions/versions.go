@@ -3,6 +3,8 @@ package versions
 
 import (
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/kechako/gosw/env"
 	"github.com/spf13/cobra"
@@ -16,9 +18,51 @@ func Command() *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			e := env.FromContext(cmd.Context())
 
-			versions := e.InstalledVersions()
-			for _, v := range versions {
-				fmt.Println(v)
+			window, err := e.SupportWindow()
+			if err != nil {
+				return err
+			}
+
+			aliases, err := e.Aliases()
+			if err != nil {
+				return err
+			}
+
+			held, err := e.HeldVersions()
+			if err != nil {
+				return err
+			}
+
+			for _, installation := range e.Installations() {
+				v := installation.Version
+
+				var notes []string
+				if installation.Origin == env.OriginSystem {
+					notes = append(notes, "system")
+				}
+				for _, alias := range aliases {
+					if alias.Version.String() == v.String() {
+						notes = append(notes, alias.Name)
+					}
+				}
+				for _, h := range held {
+					if h.String() == v.String() {
+						notes = append(notes, "held")
+					}
+				}
+				if !window.Supports(v) {
+					notes = append(notes, "unsupported")
+				}
+
+				if len(notes) > 0 {
+					fmt.Printf("%s (%s)\n", v, strings.Join(notes, ", "))
+				} else {
+					fmt.Println(v)
+				}
+			}
+
+			for _, w := range e.Warnings() {
+				fmt.Fprintf(os.Stderr, "warning: %v\n", w)
 			}
 
 			return nil
@@ -0,0 +1,106 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+)
+
+const aliasesFileName = "aliases.json"
+
+var aliasNameRegexp = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_.-]*$`)
+
+var ErrAliasNotFound = errors.New("alias is not found")
+
+// Alias is a user-defined name of a version.
+type Alias struct {
+	Name    string
+	Version *Version
+}
+
+func (env *Env) loadAliases() (map[string]string, error) {
+	aliases := make(map[string]string)
+	if err := env.readConfigFile(aliasesFileName, &aliases); err != nil {
+		return nil, err
+	}
+
+	return aliases, nil
+}
+
+// Aliases returns the aliases sorted by name.
+func (env *Env) Aliases() ([]*Alias, error) {
+	aliases, err := env.loadAliases()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*Alias, 0, len(aliases))
+	for _, name := range slices.Sorted(maps.Keys(aliases)) {
+		v, err := ParseVersion(aliases[name])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse version of alias %s: %w", name, err)
+		}
+		list = append(list, &Alias{Name: name, Version: v})
+	}
+
+	return list, nil
+}
+
+// SetAlias sets an alias of v. The name must start with a letter and must not
+// be a version.
+func (env *Env) SetAlias(name string, v *Version) error {
+	if !aliasNameRegexp.MatchString(name) {
+		return fmt.Errorf("invalid alias name: %s", name)
+	}
+	if _, err := ParseVersion(name); err == nil {
+		return fmt.Errorf("alias name must not be a version: %s", name)
+	}
+
+	aliases, err := env.loadAliases()
+	if err != nil {
+		return err
+	}
+	aliases[name] = v.String()
+
+	return env.writeConfigFile(aliasesFileName, aliases)
+}
+
+// RemoveAlias removes an alias.
+func (env *Env) RemoveAlias(name string) error {
+	aliases, err := env.loadAliases()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := aliases[name]; !ok {
+		return ErrAliasNotFound
+	}
+	delete(aliases, name)
+
+	return env.writeConfigFile(aliasesFileName, aliases)
+}
+
+// ResolveVersion returns the version of an alias or a version string.
+func (env *Env) ResolveVersion(s string) (*Version, error) {
+	aliases, err := env.loadAliases()
+	if err != nil {
+		return nil, err
+	}
+
+	if target, ok := aliases[s]; ok {
+		v, err := ParseVersion(target)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse version of alias %s: %w", s, err)
+		}
+		return v, nil
+	}
+
+	v, err := ParseVersion(s)
+	if err != nil {
+		return nil, fmt.Errorf("%s is neither a valid version nor an alias: %w", s, err)
+	}
+
+	return v, nil
+}
@@ -0,0 +1,255 @@
+package env
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CachedArchive describes an archive downloaded into the cache directory.
+type CachedArchive struct {
+	Name      string
+	Path      string
+	Version   *Version // nil if the name is not a name of a Go archive
+	Size      int64
+	ModTime   time.Time // the time the archive was last used
+	Installed bool
+}
+
+var (
+	ErrChecksumMismatch = errors.New("checksum does not match")
+	ErrUnknownArchive   = errors.New("checksum of the archive is unknown")
+	ErrNotCached        = errors.New("specified version is not cached")
+
+	errArchiveInUse = errors.New("archive is in use by another process")
+)
+
+// CachedArchives returns the archives in the cache directory, sorted by name.
+func (env *Env) CachedArchives() ([]*CachedArchive, error) {
+	entries, err := os.ReadDir(env.cacheDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	var archives []*CachedArchive
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get cached archive info: %s: %w", entry.Name(), err)
+		}
+
+		a := &CachedArchive{
+			Name:    entry.Name(),
+			Path:    filepath.Join(env.cacheDir, entry.Name()),
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		}
+		if v, err := archiveVersion(a.Name); err == nil {
+			a.Version = v
+			a.Installed = env.HasVersion(v)
+		}
+
+		archives = append(archives, a)
+	}
+
+	return archives, nil
+}
+
+// archiveVersion returns the version of an archive name such as
+// go1.22.7.linux-amd64.tar.gz.
+func archiveVersion(name string) (*Version, error) {
+	for _, ext := range []string{".tar.gz", ".tar", ".zip"} {
+		if strings.HasSuffix(name, ext) {
+			v, err := ParseVersion(strings.TrimSuffix(name, ext))
+			if err != nil {
+				return nil, err
+			}
+
+			if v.Arch == "armv6l" {
+				v = v.ForPlatform(v.OS, "arm")
+			}
+
+			return v, nil
+		}
+	}
+
+	return nil, ErrVersionSyntax
+}
+
+// archiveHeld reports whether the archive is of a held version.
+func (env *Env) archiveHeld(name string) (bool, error) {
+	v, err := archiveVersion(name)
+	if err != nil {
+		return false, nil
+	}
+
+	return env.IsHeld(v)
+}
+
+// VerifyCachedArchive verifies the SHA256 checksum of a cached archive against
+// the download list. It returns ErrUnknownArchive if the archive is not found
+// in the download list or the download list has no checksum of it.
+func (env *Env) VerifyCachedArchive(a *CachedArchive) error {
+	releases, err := env.FilterReleases(ReleaseFilter{})
+	if err != nil {
+		return err
+	}
+
+	var release *Release
+	for _, r := range releases {
+		if r.Filename == a.Name {
+			release = r
+			break
+		}
+	}
+	if release == nil || release.ChecksumSHA256 == "" {
+		return ErrUnknownArchive
+	}
+
+	sum, err := fileChecksum(a.Path)
+	if err != nil {
+		return err
+	}
+
+	if !strings.EqualFold(sum, release.ChecksumSHA256) {
+		return ErrChecksumMismatch
+	}
+
+	return nil
+}
+
+func fileChecksum(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open cached archive: %w", err)
+	}
+	defer file.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", fmt.Errorf("failed to read cached archive: %w", err)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// RemoveCachedArchives removes the cached archives of the specified version
+// for its platform and returns them. The archives of held versions are kept
+// unless Force is specified, and the archives in use by other processes are
+// kept.
+func (env *Env) RemoveCachedArchives(v *Version, opts ...RemoveOption) ([]*CachedArchive, error) {
+	o := newRemoveOptions(opts)
+
+	archives, err := env.CachedArchives()
+	if err != nil {
+		return nil, err
+	}
+
+	found := false
+	var removed []*CachedArchive
+	for _, a := range archives {
+		if a.Version == nil || !EqualVersion(a.Version, v) || !SamePlatform(a.Version, v) {
+			continue
+		}
+		found = true
+
+		if !o.force {
+			if held, err := env.archiveHeld(a.Name); err != nil {
+				return removed, err
+			} else if held {
+				continue
+			}
+		}
+
+		if err := env.removeCachedArchive(a.Path); err != nil {
+			if errors.Is(err, errArchiveInUse) {
+				continue
+			}
+			return removed, err
+		}
+		removed = append(removed, a)
+	}
+
+	if !found {
+		return nil, ErrNotCached
+	}
+
+	return removed, nil
+}
+
+// PruneCache removes the least recently used archives until the total size of
+// the cache directory is maxSize or less, and returns the removed archives.
+// The archives of held versions and the archives in use by other processes
+// are kept.
+func (env *Env) PruneCache(maxSize int64) ([]*CachedArchive, error) {
+	archives, err := env.CachedArchives()
+	if err != nil {
+		return nil, err
+	}
+
+	var total int64
+	for _, a := range archives {
+		total += a.Size
+	}
+
+	sort.Slice(archives, func(i, j int) bool {
+		return archives[i].ModTime.Before(archives[j].ModTime)
+	})
+
+	var removed []*CachedArchive
+	for _, a := range archives {
+		if total <= maxSize {
+			break
+		}
+
+		if held, err := env.archiveHeld(a.Name); err != nil {
+			return removed, err
+		} else if held {
+			continue
+		}
+
+		if err := env.removeCachedArchive(a.Path); err != nil {
+			if errors.Is(err, errArchiveInUse) {
+				continue
+			}
+			return removed, err
+		}
+		total -= a.Size
+		removed = append(removed, a)
+	}
+
+	return removed, nil
+}
+
+// removeCachedArchive removes the cached archive at path under its lock. It
+// returns errArchiveInUse if another process locks the archive.
+func (env *Env) removeCachedArchive(path string) error {
+	l, err := env.tryLockArchive(filepath.Base(path))
+	if err != nil {
+		return err
+	}
+	if l == nil {
+		return errArchiveInUse
+	}
+	defer l.unlock()
+
+	if err := os.RemoveAll(path); err != nil {
+		return fmt.Errorf("failed to remove cached archive: %s: %w", path, err)
+	}
+
+	return nil
+}
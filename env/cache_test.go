@@ -0,0 +1,213 @@
+package env
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+	"time"
+)
+
+func Test_Env_RemoveCachedArchives(t *testing.T) {
+	otherArch := "386"
+	if runtime.GOARCH == otherArch {
+		otherArch = "amd64"
+	}
+	host := "go1.22.7." + runtime.GOOS + "-" + runtime.GOARCH + ".tar.gz"
+	other := "go1.22.7." + runtime.GOOS + "-" + otherArch + ".tar.gz"
+	held := "go1.23.1." + runtime.GOOS + "-" + runtime.GOARCH + ".tar.gz"
+
+	e := newTestEnv(t, []string{"1.23.1"})
+	if err := os.MkdirAll(e.cacheDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{host, other, held} {
+		if err := os.WriteFile(filepath.Join(e.cacheDir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	v, err := ParseVersion("1.23.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Hold(v); err != nil {
+		t.Fatal(err)
+	}
+
+	cached := func() []string {
+		archives, err := e.CachedArchives()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, a := range archives {
+			names = append(names, a.Name)
+		}
+		return names
+	}
+
+	// only the archive for the platform of the version is removed
+	v, err = ParseVersion("1.22.7." + runtime.GOOS + "-" + otherArch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.RemoveCachedArchives(v); err != nil {
+		t.Fatal(err)
+	}
+	if names := cached(); slices.Contains(names, other) || !slices.Contains(names, host) {
+		t.Errorf("RemoveCachedArchives(%v): got %v, want %v removed", v, names, other)
+	}
+
+	// the archives of a held version are kept unless forced
+	v, err = ParseVersion("1.23.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed, err := e.RemoveCachedArchives(v); err != nil || len(removed) != 0 {
+		t.Errorf("RemoveCachedArchives(%v) of a held version: got %v, %v, want nothing removed", v, removed, err)
+	}
+	if _, err := e.RemoveCachedArchives(v, Force()); err != nil {
+		t.Fatal(err)
+	}
+	if names := cached(); slices.Contains(names, held) {
+		t.Errorf("RemoveCachedArchives(%v, Force()): got %v, want %v removed", v, names, held)
+	}
+
+	if _, err := e.RemoveCachedArchives(v); !errors.Is(err, ErrNotCached) {
+		t.Errorf("RemoveCachedArchives(%v) of a version not cached: got %v, want %v", v, err, ErrNotCached)
+	}
+}
+
+func Test_Env_removeCachedArchive_inUse(t *testing.T) {
+	skipIfLockUnsupported(t)
+
+	name := "go1.22.7." + runtime.GOOS + "-" + runtime.GOARCH + ".tar.gz"
+	e := newTestEnv(t, nil)
+	if err := os.MkdirAll(e.cacheDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(e.cacheDir, name)
+	if err := os.WriteFile(path, []byte("archive"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// another process downloads or extracts the archive
+	startLockHolder(t, filepath.Join(e.cacheDir, lockDirName, name+".lock"))
+
+	v, err := ParseVersion("1.22.7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed, err := e.RemoveCachedArchives(v, Force()); err != nil || len(removed) != 0 {
+		t.Errorf("RemoveCachedArchives(%v): got %v, %v, want nothing removed", v, removed, err)
+	}
+	if removed, err := e.PruneCache(0); err != nil || len(removed) != 0 {
+		t.Errorf("PruneCache(0): got %v, %v, want nothing removed", removed, err)
+	}
+	if err := e.Clean(Force()); err != nil {
+		t.Errorf("Clean(Force()): %v", err)
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("archive in use is removed: %v", err)
+	}
+}
+
+var pruneCacheTests = map[string]struct {
+	maxSize int64
+	want    []string // the versions of the removed archives
+}{
+	"under the limit": {
+		maxSize: 1000,
+		want:    nil,
+	},
+	"at the limit": {
+		maxSize: 900,
+		want:    []string{"1.23.2"},
+	},
+	"held archive": {
+		maxSize: 600,
+		want:    []string{"1.23.2", "1.21.13"},
+	},
+	"between sizes": {
+		maxSize: 500,
+		want:    []string{"1.23.2", "1.21.13", "1.23.1"},
+	},
+	"zero": {
+		maxSize: 0,
+		want:    []string{"1.23.2", "1.21.13", "1.23.1"},
+	},
+}
+
+func Test_Env_PruneCache(t *testing.T) {
+	// the archives from the least recently used, which is not the order of
+	// the versions, and the archive of 1.22.7 is held
+	archives := []struct {
+		version string
+		size    int
+	}{
+		{"1.23.2", 100},
+		{"1.22.7", 200},
+		{"1.21.13", 300},
+		{"1.23.1", 400},
+	}
+	name := func(version string) string {
+		return "go" + version + "." + runtime.GOOS + "-" + runtime.GOARCH + ".tar.gz"
+	}
+
+	for caseName, tt := range pruneCacheTests {
+		t.Run(caseName, func(t *testing.T) {
+			e := newTestEnv(t, []string{"1.22.7"})
+			if err := os.MkdirAll(e.cacheDir, 0755); err != nil {
+				t.Fatal(err)
+			}
+
+			base := time.Now().Add(-time.Hour)
+			for i, a := range archives {
+				path := filepath.Join(e.cacheDir, name(a.version))
+				if err := os.WriteFile(path, make([]byte, a.size), 0644); err != nil {
+					t.Fatal(err)
+				}
+				mtime := base.Add(time.Duration(i) * time.Minute)
+				if err := os.Chtimes(path, mtime, mtime); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			v, err := ParseVersion("1.22.7")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := e.Hold(v); err != nil {
+				t.Fatal(err)
+			}
+
+			removed, err := e.PruneCache(tt.maxSize)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, a := range removed {
+				got = append(got, a.Name)
+			}
+			var want []string
+			for _, version := range tt.want {
+				want = append(want, name(version))
+			}
+			if !slices.Equal(got, want) {
+				t.Errorf("PruneCache(%d): got %v, want %v", tt.maxSize, got, want)
+			}
+
+			for _, a := range archives {
+				_, err := os.Stat(filepath.Join(e.cacheDir, name(a.version)))
+				if exists := err == nil; exists == slices.Contains(tt.want, a.version) {
+					t.Errorf("PruneCache(%d): %s exists: %v", tt.maxSize, a.version, exists)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package env
+
+// ReleaseChanges describes the changes of the download list by an update.
+// Added and Removed hold the archives for the running platform in ascending
+// order.
+type ReleaseChanges struct {
+	Added   []*Release
+	Removed []*Release
+}
+
+// Empty reports whether the download list is not changed.
+func (c *ReleaseChanges) Empty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0
+}
+
+// NewStable returns the added stable releases.
+func (c *ReleaseChanges) NewStable() []*Release {
+	var releases []*Release
+	for _, r := range c.Added {
+		if r.Stable {
+			releases = append(releases, r)
+		}
+	}
+
+	return releases
+}
+
+// NewPrereleases returns the added unstable releases.
+func (c *ReleaseChanges) NewPrereleases() []*Release {
+	var releases []*Release
+	for _, r := range c.Added {
+		if !r.Stable {
+			releases = append(releases, r)
+		}
+	}
+
+	return releases
+}
+
+// InstalledLineUpdate returns the newest installed version of the minor line
+// of r if r is a new patch release of the line, or nil.
+func (env *Env) InstalledLineUpdate(r *Release) *Version {
+	if !r.Stable || r.Version.Type != Stable {
+		return nil
+	}
+
+	var latest *Version
+	for _, v := range env.InstalledVersions() {
+		if v.Arch != "" || !EqualMinorVersion(v, r.Version) {
+			continue
+		}
+		latest = v
+	}
+
+	if latest == nil || CompareVersion(r.Version, latest) <= 0 {
+		return nil
+	}
+
+	return latest
+}
+
+// diffReleases returns the archives for the running platform added to or
+// removed from old.
+func diffReleases(old, latest []*Release) *ReleaseChanges {
+	f := HostReleaseFilter()
+	old = filterReleases(old, f)
+	latest = filterReleases(latest, f)
+
+	oldVersions := make(map[string]bool, len(old))
+	for _, r := range old {
+		oldVersions[r.Version.String()] = true
+	}
+	newVersions := make(map[string]bool, len(latest))
+	for _, r := range latest {
+		newVersions[r.Version.String()] = true
+	}
+
+	changes := &ReleaseChanges{}
+	for _, r := range latest {
+		if !oldVersions[r.Version.String()] {
+			changes.Added = append(changes.Added, r)
+		}
+	}
+	for _, r := range old {
+		if !newVersions[r.Version.String()] {
+			changes.Removed = append(changes.Removed, r)
+		}
+	}
+
+	return changes
+}
@@ -0,0 +1,53 @@
+package env
+
+import (
+	"runtime"
+	"testing"
+)
+
+func hostRelease(t *testing.T, s string, stable bool) *Release {
+	t.Helper()
+
+	v, err := ParseVersion(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &Release{
+		Version: v,
+		Stable:  stable,
+		OS:      runtime.GOOS,
+		Arch:    releaseArch(runtime.GOARCH),
+		Kind:    "archive",
+	}
+}
+
+func Test_diffReleases(t *testing.T) {
+	old := []*Release{
+		hostRelease(t, "1.22.6", true),
+		hostRelease(t, "1.23rc1", false),
+		hostRelease(t, "1.23rc2", false),
+	}
+	latest := []*Release{
+		hostRelease(t, "1.22.6", true),
+		hostRelease(t, "1.22.7", true),
+		hostRelease(t, "1.23rc2", false),
+		hostRelease(t, "1.23rc3", false),
+	}
+
+	changes := diffReleases(old, latest)
+
+	if got := changes.NewStable(); len(got) != 1 || got[0].Version.String() != "1.22.7" {
+		t.Errorf("NewStable(): got %v, want [1.22.7]", got)
+	}
+	if got := changes.NewPrereleases(); len(got) != 1 || got[0].Version.String() != "1.23rc3" {
+		t.Errorf("NewPrereleases(): got %v, want [1.23rc3]", got)
+	}
+	if got := changes.Removed; len(got) != 1 || got[0].Version.String() != "1.23rc1" {
+		t.Errorf("Removed: got %v, want [1.23rc1]", got)
+	}
+
+	if !diffReleases(latest, latest).Empty() {
+		t.Error("diffReleases(latest, latest): got changes, want empty")
+	}
+}
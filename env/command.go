@@ -0,0 +1,119 @@
+package env
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// versionedCommands are the commands linked with the version in their names.
+var versionedCommands = []string{"go", "gofmt"}
+
+// UpdateCommands links the versioned commands such as go1.22.7, gofmt1.24rc1
+// and go-head in the command directory to the commands of the installed
+// versions, and removes the links of versions that are no longer installed.
+// It does nothing unless the command directory is set.
+//
+// Only symbolic links named after the versioned commands are replaced or
+// removed. Other files in the command directory, including links such as go
+// that point into the env root, are left untouched.
+func (env *Env) UpdateCommands() error {
+	if env.commandDir == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(env.commandDir, 0755); err != nil {
+		return fmt.Errorf("failed to create command directory: %w", err)
+	}
+
+	commands := make(map[string]string)
+	for _, v := range env.InstalledVersions() {
+		for name, target := range env.versionCommands(v) {
+			commands[name] = target
+		}
+	}
+
+	entries, err := os.ReadDir(env.commandDir)
+	if err != nil {
+		return fmt.Errorf("failed to read command directory: %w", err)
+	}
+	for _, entry := range entries {
+		if !isVersionedCommand(entry.Name()) {
+			continue
+		}
+
+		path := filepath.Join(env.commandDir, entry.Name())
+		target, err := os.Readlink(path)
+		if err != nil {
+			continue
+		}
+		// links of the versioned commands are replaced even if they point to
+		// another root, e.g. after migration
+		if commands[entry.Name()] == target {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to remove command %s: %w", entry.Name(), err)
+		}
+	}
+
+	for name, target := range commands {
+		path := filepath.Join(env.commandDir, name)
+		if _, err := os.Lstat(path); err == nil {
+			// the link is up to date, or the file is not managed by gosw
+			continue
+		}
+
+		if err := os.Symlink(target, path); err != nil {
+			return fmt.Errorf("failed to create command %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// versionCommands returns the paths of the versioned commands of v keyed by
+// the command names.
+func (env *Env) versionCommands(v *Version) map[string]string {
+	suffix := v.String()
+	if v.Type == Head {
+		suffix = strings.TrimPrefix(headVersion, "go")
+	}
+
+	var exe string
+	if goos, _ := v.Platform(); goos == "windows" {
+		exe = ".exe"
+	}
+
+	binDir := filepath.Join(env.versionGoRoot(v), "bin")
+
+	commands := make(map[string]string, len(versionedCommands))
+	for _, cmd := range versionedCommands {
+		commands[cmd+suffix+exe] = filepath.Join(binDir, cmd+exe)
+	}
+
+	return commands
+}
+
+// isVersionedCommand reports whether name is a name of a versioned command
+// such as go1.22.7, gofmt1.24rc1.exe or go-head.
+func isVersionedCommand(name string) bool {
+	name = strings.TrimSuffix(name, ".exe")
+	for _, cmd := range versionedCommands {
+		suffix, ok := strings.CutPrefix(name, cmd)
+		if !ok || suffix == "" {
+			continue
+		}
+		if suffix == strings.TrimPrefix(headVersion, "go") {
+			return true
+		}
+		if suffix[0] >= '0' && suffix[0] <= '9' {
+			if _, err := ParseVersion(suffix); err == nil {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,88 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var isVersionedCommandTests = map[string]bool{
+	"go1.22.7":                  true,
+	"gofmt1.24rc1":              true,
+	"go1.22.7.exe":              true,
+	"go-head":                   true,
+	"gofmt-head":                true,
+	"go1.22.7.linux-386":        true,
+	"go":                        false,
+	"gofmt":                     false,
+	"go.exe":                    false,
+	"gopls":                     false,
+	"goimports":                 false,
+	"go-tip":                    false,
+	"go1.22.x":                  false,
+	"staticcheck":               false,
+	"gofmt1.22.7.linux-386.exe": true,
+}
+
+func Test_isVersionedCommand(t *testing.T) {
+	for name, want := range isVersionedCommandTests {
+		if got := isVersionedCommand(name); got != want {
+			t.Errorf("isVersionedCommand(%v): got %v, want %v", name, got, want)
+		}
+	}
+}
+
+func Test_Env_UpdateCommands(t *testing.T) {
+	cmdDir := filepath.Join(t.TempDir(), "bin")
+	e := newTestEnv(t, []string{"1.22.7"}, WithCommandDir(cmdDir))
+	if err := os.MkdirAll(cmdDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// links created by the user point into the env root too
+	userLinks := map[string]string{
+		"go":    filepath.Join(e.envRoot, "current", "bin", "go"),
+		"gofmt": filepath.Join(e.envRoot, "current", "bin", "gofmt"),
+		"gopls": filepath.Join(e.envRoot, "go1.22.7", "bin", "gopls"),
+	}
+	for name, target := range userLinks {
+		if err := os.Symlink(target, filepath.Join(cmdDir, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// a link of a version that is no longer installed
+	stale := filepath.Join(cmdDir, "go1.21.3")
+	if err := os.Symlink(filepath.Join(e.envRoot, "go1.21.3", "go", "bin", "go"), stale); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := e.UpdateCommands(); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range userLinks {
+		got, err := os.Readlink(filepath.Join(cmdDir, name))
+		if err != nil {
+			t.Errorf("user link %s: %v", name, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("user link %s: got %v, want %v", name, got, want)
+		}
+	}
+
+	if _, err := os.Lstat(stale); !os.IsNotExist(err) {
+		t.Errorf("stale link go1.21.3 is not removed: %v", err)
+	}
+
+	for name, want := range e.versionCommands(e.InstalledVersions()[0]) {
+		got, err := os.Readlink(filepath.Join(cmdDir, name))
+		if err != nil {
+			t.Errorf("command %s: %v", name, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("command %s: got %v, want %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package env
+
+import (
+	"net/http"
+	"time"
+)
+
+const downloadListMetaFileName = "downloads.meta.json"
+
+const (
+	// downloadListTimeout is the time limit of a request for the download
+	// list.
+	downloadListTimeout = 30 * time.Second
+
+	// failedUpdateInterval is the time to wait after the automatic update of
+	// the download list failed before trying again.
+	failedUpdateInterval = time.Hour
+)
+
+var downloadListClient = &http.Client{Timeout: downloadListTimeout}
+
+// downloadListMeta holds the metadata of the downloaded download list, which
+// is used to make a conditional request.
+type downloadListMeta struct {
+	FetchedAt    time.Time `json:"fetched_at"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	FailedAt     time.Time `json:"failed_at,omitzero"` // the last time the automatic update failed
+}
+
+func (env *Env) loadDownloadListMeta() (*downloadListMeta, error) {
+	var meta downloadListMeta
+	if err := env.readConfigFile(downloadListMetaFileName, &meta); err != nil {
+		return nil, err
+	}
+
+	return &meta, nil
+}
+
+func (env *Env) saveDownloadListMeta(meta *downloadListMeta) error {
+	return env.writeConfigFile(downloadListMetaFileName, meta)
+}
+
+// autoUpdateDownloadList updates the download list automatically, and
+// records the time of a failure so that the following commands do not retry
+// soon.
+func (env *Env) autoUpdateDownloadList() error {
+	_, err := env.UpdateDownloadList()
+	if err == nil {
+		return nil
+	}
+
+	if meta, merr := env.loadDownloadListMeta(); merr == nil {
+		meta.FailedAt = time.Now()
+		env.saveDownloadListMeta(meta)
+	}
+
+	return err
+}
+
+// AutoUpdateError returns the error of the automatic update of the download
+// list in the env, or nil if it is not tried or succeeds. The releases are
+// loaded from the previous download list or the snapshot after the failure.
+func (env *Env) AutoUpdateError() error {
+	return env.autoUpdateErr
+}
+
+// updateFailedRecently reports whether the automatic update of the download
+// list failed recently.
+func (env *Env) updateFailedRecently() bool {
+	meta, err := env.loadDownloadListMeta()
+	if err != nil {
+		return false
+	}
+
+	return time.Since(meta.FailedAt) < failedUpdateInterval
+}
@@ -7,35 +7,63 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"time"
 )
 
 var (
+	// DefaultEnvRoot is the system-wide env root.
 	DefaultEnvRoot         = "/usr/local/go"
 	DefaultVersionLinkName = "current"
+	DefaultReleasesTTL     = 24 * time.Hour
 )
 
 type Env struct {
 	envRoot     string
+	systemRoot  string
 	verLinkName string
 	confDir     string
 	cacheDir    string
 
+	maxCacheSize  int64
+	removeArchive bool
+	releasesTTL   time.Duration
+	offline       bool
+	minorLinks    bool
+	commandDir    string
+	mirrors       []string
+	hooks         Hooks
+	installGroup  string
+	installUmask  *os.FileMode
+	lockTimeout   time.Duration
+	fallback      FallbackPolicy
+
 	installedVersions map[string]*Version
+	systemVersions    map[string]bool // keys of the versions in the system root
 	releases          []*Release
+	refreshed         bool  // whether the download list is updated in this process
+	autoUpdateErr     error // the failure of the automatic update in this process
+	fromSnapshot      bool  // whether the releases are loaded from the bundled snapshot
+	releaseWarnings   []*Warning
+	installWarnings   []*Warning
+	heldLocks         map[string]*fileLock // locks held by this process keyed by the paths
 }
 
 func New(opts ...Option) (*Env, error) {
 	env := &Env{
 		envRoot:           DefaultEnvRoot,
 		verLinkName:       DefaultVersionLinkName,
+		releasesTTL:       DefaultReleasesTTL,
+		lockTimeout:       DefaultLockTimeout,
+		fallback:          DefaultFallbackPolicy,
 		installedVersions: make(map[string]*Version),
+		systemVersions:    make(map[string]bool),
 	}
 	for _, opt := range opts {
 		opt.apply(env)
 	}
 
 	if env.confDir == "" {
-		confDir, err := getConfPath()
+		confDir, err := DefaultConfigDir()
 		if err != nil {
 			return nil, err
 		}
@@ -54,45 +82,68 @@ func New(opts ...Option) (*Env, error) {
 }
 
 func (env *Env) init() error {
-	versions, err := installedVersions(env.envRoot)
+	if env.systemRoot != "" && env.systemRoot != env.envRoot {
+		versions, warnings, err := installedVersions(env.systemRoot)
+		if err != nil {
+			return err
+		}
+		env.installWarnings = append(env.installWarnings, warnings...)
+
+		for _, version := range versions {
+			env.installedVersions[version.String()] = version
+			env.systemVersions[version.String()] = true
+		}
+	}
+
+	versions, warnings, err := installedVersions(env.envRoot)
 	if err != nil {
 		return err
 	}
+	env.installWarnings = append(env.installWarnings, warnings...)
 
+	// the versions in the env root take precedence over the system root
 	for _, version := range versions {
 		env.installedVersions[version.String()] = version
+		delete(env.systemVersions, version.String())
 	}
 
 	return nil
 }
 
-func installedVersions(root string) ([]*Version, error) {
+// installedVersions returns the versions installed in root. Directories whose
+// names cannot be parsed are skipped and reported as warnings, and symbolic
+// links are ignored.
+func installedVersions(root string) ([]*Version, []*Warning, error) {
 	dirs, err := filepath.Glob(filepath.Join(root, "/go*"))
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	var versions []*Version
+	var warnings []*Warning
 	for _, dir := range dirs {
-		if info, err := os.Stat(dir); err == nil {
+		if info, err := os.Lstat(dir); err == nil {
+			// symbolic links such as the minor links are not installations
 			if !info.IsDir() {
 				continue
 			}
 			str := info.Name()
 			version, err := ParseVersion(str)
 			if err != nil {
-				fmt.Println(err, str)
+				warnings = append(warnings, &Warning{
+					Source: root,
+					Name:   str,
+					Err:    err,
+				})
 				continue
 			}
 			versions = append(versions, version)
 		}
 	}
 
-	sort.Slice(versions, func(i, j int) bool {
-		return CompareVersion(versions[i], versions[j]) < 0
-	})
+	sortVersions(versions)
 
-	return versions, nil
+	return versions, warnings, nil
 }
 
 func (env *Env) InstalledVersions() []*Version {
@@ -101,39 +152,101 @@ func (env *Env) InstalledVersions() []*Version {
 		versions = append(versions, &(*v))
 	}
 
-	sort.Slice(versions, func(i, j int) bool {
-		return CompareVersion(versions[i], versions[j]) < 0
-	})
+	sortVersions(versions)
 
 	return versions
 }
 
+// sortVersions sorts versions in ascending order, and versions for the running
+// platform come first among the same versions.
+func sortVersions(versions []*Version) {
+	sort.Slice(versions, func(i, j int) bool {
+		if c := CompareVersion(versions[i], versions[j]); c != 0 {
+			return c < 0
+		}
+		return versions[i].String() < versions[j].String()
+	})
+}
+
 func (env *Env) HasVersion(v *Version) bool {
-	_, ok := env.installedVersions[v.String()]
-	if !ok {
-		return false
+	return env.installedVersion(v) != nil
+}
+
+// installedVersion returns the installed version equal to v, which is named
+// as installed, e.g. 1.20 for 1.20.0.
+func (env *Env) installedVersion(v *Version) *Version {
+	if installed, ok := env.installedVersions[v.String()]; ok {
+		return installed
 	}
 
-	return true
+	for _, installed := range env.installedVersions {
+		if EqualVersion(installed, v) && SamePlatform(installed, v) {
+			return installed
+		}
+	}
+
+	return nil
 }
 
 func (env *Env) Switch(v *Version) error {
-	if !env.HasVersion(v) {
+	installed := env.installedVersion(v)
+	if installed == nil {
 		return errors.New("specified version is not installed")
 	}
 
-	return env.makeLink(v)
+	if err := env.makeLink(installed); err != nil {
+		return err
+	}
+
+	env.runHook("post-switch", env.hooks.PostSwitch, installed)
+
+	return nil
 }
 
-func (env *Env) Clean() error {
+var ErrNoCurrentVersion = errors.New("current version is not set")
+
+// CurrentVersion returns the version the version link points to.
+func (env *Env) CurrentVersion() (*Version, error) {
+	target, err := os.Readlink(env.linkPath())
+	if err != nil {
+		return nil, ErrNoCurrentVersion
+	}
+
+	v, err := ParseVersion(filepath.Base(target))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse current version: %s: %w", target, err)
+	}
+
+	return v, nil
+}
+
+// Clean removes the cached archives. The archives of held versions are kept
+// unless Force is specified, and the archives in use by other processes are
+// kept.
+func (env *Env) Clean(opts ...RemoveOption) error {
+	o := newRemoveOptions(opts)
+
 	archives, err := filepath.Glob(filepath.Join(env.cacheDir, "/*"))
 	if err != nil {
 		return err
 	}
 
 	for _, archive := range archives {
-		if err := os.RemoveAll(archive); err != nil {
-			return fmt.Errorf("failed to remove cached archive: %s: %w", archive, err)
+		if filepath.Base(archive) == lockDirName {
+			continue
+		}
+		if !o.force {
+			held, err := env.archiveHeld(filepath.Base(archive))
+			if err != nil {
+				return err
+			}
+			if held {
+				continue
+			}
+		}
+
+		if err := env.removeCachedArchive(archive); err != nil && !errors.Is(err, errArchiveInUse) {
+			return err
 		}
 	}
 
@@ -144,54 +257,88 @@ func (env *Env) linkPath() string {
 	return filepath.Join(env.envRoot, env.verLinkName)
 }
 
+// versionGoRoot returns the GOROOT of v, which is in the system root if v is
+// installed there.
 func (env *Env) versionGoRoot(v *Version) string {
-	var name string
-	if v.Type == Head {
-		name = v.String()
-	} else {
-		name = "go" + v.String()
+	if env.systemVersions[v.String()] {
+		return filepath.Join(env.systemRoot, versionDirName(v))
 	}
-	return filepath.Join(env.envRoot, name)
+
+	return filepath.Join(env.envRoot, versionDirName(v))
 }
 
-func (env *Env) fixBrokenLink() error {
-	path := env.linkPath()
+// versionDirName returns the name of the directory where v is installed.
+func versionDirName(v *Version) string {
+	if v.Type == Head {
+		return v.String()
+	}
+
+	return "go" + v.String()
+}
 
-	if _, err := os.Stat(path); err == nil {
-		// link target is not broken
-		return nil
+// refreshLinks updates the links that depend on the installed versions.
+func (env *Env) refreshLinks() error {
+	if err := env.fixBrokenLink(); err != nil {
+		return err
 	}
 
-	versions := env.InstalledVersions()
-	if len(versions) == 0 {
-		return nil
+	if _, err := env.UpdateChannelLinks(); err != nil {
+		return err
 	}
 
-	if err := env.makeLink(versions[len(versions)-1]); err != nil {
+	if err := env.UpdateMinorLinks(); err != nil {
 		return err
 	}
 
-	return nil
+	return env.UpdateCommands()
 }
 
 func (env *Env) makeLink(v *Version) error {
+	if err := env.makeNamedLink(env.verLinkName, v); err != nil {
+		return err
+	}
+
+	return env.recordHistory(v)
+}
+
+// makeNamedLink points the link named name in the env root to v.
+func (env *Env) makeNamedLink(name string, v *Version) error {
 	goRoot := env.versionGoRoot(v)
 
-	path := env.linkPath()
-	if _, err := os.Lstat(path); err == nil {
-		if err := os.Remove(path); err != nil {
-			return fmt.Errorf("failed to remove current symbolic link: %w", err)
-		}
+	path := filepath.Join(env.envRoot, name)
+
+	l, err := env.lockPath(path)
+	if err != nil {
+		return err
 	}
+	defer l.unlock()
 
-	if err := os.Symlink(goRoot, path); err != nil {
+	// create a new link and rename it over the old one, so that the link
+	// always exists
+	tmpPath := path + ".tmp"
+	os.Remove(tmpPath)
+	if err := os.Symlink(goRoot, tmpPath); err != nil {
 		return fmt.Errorf("failed to create new symbolic link: %w", err)
 	}
 
+	if err := os.Rename(tmpPath, path); err != nil {
+		// some platforms cannot replace a link to a directory
+		if rerr := os.Remove(path); rerr != nil && !errors.Is(rerr, os.ErrNotExist) {
+			os.Remove(tmpPath)
+			return fmt.Errorf("failed to replace %s symbolic link: %w", name, err)
+		}
+		if err := os.Rename(tmpPath, path); err != nil {
+			os.Remove(tmpPath)
+			return fmt.Errorf("failed to replace %s symbolic link: %w", name, err)
+		}
+	}
+
 	return nil
 }
 
-func getConfPath() (string, error) {
+// DefaultConfigDir returns the config directory used when WithConfigDir is not
+// specified.
+func DefaultConfigDir() (string, error) {
 	confDir, err := os.UserConfigDir()
 	if err != nil {
 		userDir, err := os.UserHomeDir()
@@ -205,6 +352,33 @@ func getConfPath() (string, error) {
 	return confDir + "/gosw", nil
 }
 
+// DefaultUserEnvRoot returns the per-user env root, which is gosw in
+// $XDG_DATA_HOME, or ~/.local/share/gosw if XDG_DATA_HOME is not set.
+func DefaultUserEnvRoot() (string, error) {
+	if dataDir := os.Getenv("XDG_DATA_HOME"); filepath.IsAbs(dataDir) {
+		return filepath.Join(dataDir, "gosw"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+
+	return filepath.Join(home, ".local", "share", "gosw"), nil
+}
+
+// LinkBinDir returns the bin directory of the version link, which should be
+// in PATH.
+func (env *Env) LinkBinDir() string {
+	return filepath.Join(env.linkPath(), "bin")
+}
+
+// CommandDir returns the directory of the versioned commands, which is empty
+// if they are disabled.
+func (env *Env) CommandDir() string {
+	return env.commandDir
+}
+
 func getCachePath() string {
 	cacheDir, err := os.UserCacheDir()
 	if err != nil {
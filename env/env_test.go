@@ -0,0 +1,44 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestMain runs the test binary as a process holding a lock if requested by
+// the lock tests.
+func TestMain(m *testing.M) {
+	if path := os.Getenv(lockHelperEnv); path != "" {
+		holdLock(path)
+		return
+	}
+
+	os.Exit(m.Run())
+}
+
+// newTestEnv returns an env rooted in a temporary directory where the
+// specified versions are installed.
+func newTestEnv(t *testing.T, versions []string, opts ...Option) *Env {
+	t.Helper()
+
+	dir := t.TempDir()
+	root := filepath.Join(dir, "root")
+	for _, s := range versions {
+		if err := os.MkdirAll(filepath.Join(root, "go"+s, "bin"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	opts = append([]Option{
+		WithEnvRoot(root),
+		WithConfigDir(filepath.Join(dir, "config")),
+		WithCacheDir(filepath.Join(dir, "cache")),
+	}, opts...)
+	e, err := New(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return e
+}
@@ -0,0 +1,95 @@
+package env
+
+import (
+	"fmt"
+	"os"
+)
+
+// FallbackPolicy selects the version the version link points to when the
+// current version is removed.
+type FallbackPolicy string
+
+const (
+	// FallbackStable selects the newest installed stable version.
+	FallbackStable FallbackPolicy = "stable"
+	// FallbackPrevious selects the version used before the removed one, or
+	// the newest stable version if there is none.
+	FallbackPrevious FallbackPolicy = "previous"
+	// FallbackMinor selects the newest installed version of the same minor
+	// line as the removed one, preferring stable versions, or the newest
+	// stable version if there is none.
+	FallbackMinor FallbackPolicy = "minor"
+	// FallbackNone removes the version link.
+	FallbackNone FallbackPolicy = "none"
+)
+
+// DefaultFallbackPolicy is the default fallback policy.
+var DefaultFallbackPolicy = FallbackStable
+
+// ParseFallbackPolicy parses the name of a fallback policy.
+func ParseFallbackPolicy(s string) (FallbackPolicy, error) {
+	switch p := FallbackPolicy(s); p {
+	case FallbackStable, FallbackPrevious, FallbackMinor, FallbackNone:
+		return p, nil
+	}
+
+	return "", fmt.Errorf("unknown fallback policy: %s", s)
+}
+
+// fixBrokenLink points the version link whose target is removed to the
+// version selected by the fallback policy, or removes the link if no version
+// is selected.
+func (env *Env) fixBrokenLink() error {
+	path := env.linkPath()
+
+	if _, err := os.Lstat(path); err != nil {
+		// no link to fix
+		return nil
+	}
+	if _, err := os.Stat(path); err == nil {
+		// link target is not broken
+		return nil
+	}
+
+	removed, _ := readLinkVersion(path)
+
+	if v := env.fallbackVersion(removed); v != nil {
+		return env.makeLink(v)
+	}
+
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to remove %s symbolic link: %w", env.verLinkName, err)
+	}
+
+	return nil
+}
+
+// fallbackVersion returns the version selected by the fallback policy when
+// removed is removed, or nil if no version is selected.
+func (env *Env) fallbackVersion(removed *Version) *Version {
+	switch env.fallback {
+	case FallbackNone:
+		return nil
+	case FallbackPrevious:
+		if v, err := env.PreviousVersion(); err == nil {
+			return v
+		}
+	case FallbackMinor:
+		if removed != nil {
+			var latest *Version
+			for _, v := range env.InstalledVersions() {
+				if !EqualMinorVersion(v, removed) || !SamePlatform(v, removed) {
+					continue
+				}
+				if latest == nil || v.Type == Stable || latest.Type != Stable {
+					latest = v
+				}
+			}
+			if latest != nil {
+				return latest
+			}
+		}
+	}
+
+	return env.channelVersion(ChannelStable)
+}
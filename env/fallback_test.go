@@ -0,0 +1,112 @@
+package env
+
+import (
+	"testing"
+)
+
+var fallbackVersionTests = map[string]struct {
+	policy   FallbackPolicy
+	versions []string
+	history  []string // the switches, the oldest first
+	removed  string
+	want     string // empty if no version is selected
+}{
+	"stable": {
+		policy:   FallbackStable,
+		versions: []string{"1.21.13", "1.22.5", "1.23rc1", "-head"},
+		history:  []string{"1.21.13", "1.22.7"},
+		removed:  "1.22.7",
+		want:     "1.22.5",
+	},
+	"stable without stable versions": {
+		policy:   FallbackStable,
+		versions: []string{"1.23rc1", "-head"},
+		removed:  "1.22.7",
+		want:     "",
+	},
+	"previous": {
+		policy:   FallbackPrevious,
+		versions: []string{"1.21.13", "1.22.5", "1.23rc1", "-head"},
+		history:  []string{"1.23rc1", "1.21.13", "1.22.7"},
+		removed:  "1.22.7",
+		want:     "1.21.13",
+	},
+	"previous head": {
+		policy:   FallbackPrevious,
+		versions: []string{"1.21.13", "1.22.5", "-head"},
+		history:  []string{"1.21.13", "go-head", "1.22.7"},
+		removed:  "1.22.7",
+		want:     "go-head",
+	},
+	"previous without history": {
+		policy:   FallbackPrevious,
+		versions: []string{"1.21.13", "1.22.5", "1.23rc1", "-head"},
+		removed:  "1.22.7",
+		want:     "1.22.5",
+	},
+	"previous uninstalled": {
+		policy:   FallbackPrevious,
+		versions: []string{"1.21.13", "1.22.5"},
+		history:  []string{"1.20.14", "1.22.7"},
+		removed:  "1.22.7",
+		want:     "1.22.5",
+	},
+	"minor": {
+		policy:   FallbackMinor,
+		versions: []string{"1.21.13", "1.22rc2", "1.22.5", "1.23rc1", "-head"},
+		history:  []string{"1.21.13", "1.22.7"},
+		removed:  "1.22.7",
+		want:     "1.22.5",
+	},
+	"minor rc": {
+		policy:   FallbackMinor,
+		versions: []string{"1.21.13", "1.22.5", "1.23rc1", "-head"},
+		removed:  "1.23.0",
+		want:     "1.23rc1",
+	},
+	"minor without the line": {
+		policy:   FallbackMinor,
+		versions: []string{"1.21.13", "1.22.5", "1.23rc1", "-head"},
+		removed:  "1.24.1",
+		want:     "1.22.5",
+	},
+	"none": {
+		policy:   FallbackNone,
+		versions: []string{"1.21.13", "1.22.5", "1.23rc1", "-head"},
+		history:  []string{"1.21.13", "1.22.7"},
+		removed:  "1.22.7",
+		want:     "",
+	},
+}
+
+func Test_Env_fallbackVersion(t *testing.T) {
+	for name, tt := range fallbackVersionTests {
+		t.Run(name, func(t *testing.T) {
+			e := newTestEnv(t, tt.versions, WithFallbackPolicy(tt.policy))
+
+			var records []*historyRecord
+			for _, s := range tt.history {
+				records = append(records, &historyRecord{Version: s})
+			}
+			if err := e.writeConfigFile(historyFileName, records); err != nil {
+				t.Fatal(err)
+			}
+
+			removed, err := ParseVersion(tt.removed)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := e.fallbackVersion(removed)
+			if tt.want == "" {
+				if got != nil {
+					t.Errorf("fallbackVersion(%v): got %v, want nil", removed, got)
+				}
+				return
+			}
+			if got == nil || got.String() != tt.want {
+				t.Errorf("fallbackVersion(%v): got %v, want %v", removed, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const historyFileName = "history.json"
+
+// maxHistoryEntries is the number of the switches kept in the history.
+const maxHistoryEntries = 100
+
+var ErrNoPreviousVersion = errors.New("no previous version in the history")
+
+// HistoryEntry is a switch of the version link.
+type HistoryEntry struct {
+	Version *Version
+	Time    time.Time
+}
+
+type historyRecord struct {
+	Version string    `json:"version"`
+	Time    time.Time `json:"time"`
+}
+
+func (env *Env) loadHistory() ([]*historyRecord, error) {
+	var records []*historyRecord
+	if err := env.readConfigFile(historyFileName, &records); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// recordHistory appends a switch to v to the history unless the last switch
+// is to v.
+func (env *Env) recordHistory(v *Version) error {
+	records, err := env.loadHistory()
+	if err != nil {
+		return err
+	}
+
+	if len(records) > 0 && records[len(records)-1].Version == v.String() {
+		return nil
+	}
+
+	records = append(records, &historyRecord{
+		Version: v.String(),
+		Time:    time.Now(),
+	})
+	if len(records) > maxHistoryEntries {
+		records = records[len(records)-maxHistoryEntries:]
+	}
+
+	return env.writeConfigFile(historyFileName, records)
+}
+
+// History returns the switches of the version link, the newest first.
+func (env *Env) History() ([]*HistoryEntry, error) {
+	records, err := env.loadHistory()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*HistoryEntry, 0, len(records))
+	for i := len(records) - 1; i >= 0; i-- {
+		v, err := ParseVersion(records[i].Version)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse version in history: %w", err)
+		}
+		entries = append(entries, &HistoryEntry{
+			Version: v,
+			Time:    records[i].Time,
+		})
+	}
+
+	return entries, nil
+}
+
+// PreviousVersion returns the installed version used before the current
+// version.
+func (env *Env) PreviousVersion() (*Version, error) {
+	entries, err := env.History()
+	if err != nil {
+		return nil, err
+	}
+
+	current, _ := env.CurrentVersion()
+	for _, entry := range entries {
+		if current != nil && entry.Version.String() == current.String() {
+			continue
+		}
+		if env.HasVersion(entry.Version) {
+			return entry.Version, nil
+		}
+	}
+
+	return nil, ErrNoPreviousVersion
+}
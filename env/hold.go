@@ -0,0 +1,123 @@
+package env
+
+import (
+	"errors"
+	"slices"
+)
+
+const holdsFileName = "holds.json"
+
+var ErrVersionHeld = errors.New("specified version is held")
+
+// RemoveOption configures the removal of versions and cached archives.
+type RemoveOption interface {
+	apply(opts *removeOptions)
+}
+
+type removeOptions struct {
+	force bool
+}
+
+type removeOptionFunc func(opts *removeOptions)
+
+func (f removeOptionFunc) apply(opts *removeOptions) {
+	f(opts)
+}
+
+// Force makes held versions, their cached archives and the current version
+// removable.
+func Force() RemoveOption {
+	return removeOptionFunc(func(opts *removeOptions) {
+		opts.force = true
+	})
+}
+
+func newRemoveOptions(opts []RemoveOption) *removeOptions {
+	o := &removeOptions{}
+	for _, opt := range opts {
+		opt.apply(o)
+	}
+
+	return o
+}
+
+func (env *Env) loadHolds() ([]string, error) {
+	var holds []string
+	if err := env.readConfigFile(holdsFileName, &holds); err != nil {
+		return nil, err
+	}
+
+	return holds, nil
+}
+
+// HeldVersions returns the held versions in ascending order.
+func (env *Env) HeldVersions() ([]*Version, error) {
+	holds, err := env.loadHolds()
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]*Version, 0, len(holds))
+	for _, s := range holds {
+		v, err := ParseVersion(s)
+		if err != nil {
+			continue
+		}
+		versions = append(versions, v)
+	}
+	sortVersions(versions)
+
+	return versions, nil
+}
+
+// IsHeld reports whether v is held.
+func (env *Env) IsHeld(v *Version) (bool, error) {
+	holds, err := env.loadHolds()
+	if err != nil {
+		return false, err
+	}
+
+	return slices.Contains(holds, v.String()), nil
+}
+
+// Hold holds an installed version to protect it from being uninstalled or
+// upgraded.
+func (env *Env) Hold(v *Version) error {
+	installed := env.installedVersion(v)
+	if installed == nil {
+		return errors.New("specified version is not installed")
+	}
+
+	holds, err := env.loadHolds()
+	if err != nil {
+		return err
+	}
+
+	if slices.Contains(holds, installed.String()) {
+		return nil
+	}
+	holds = append(holds, installed.String())
+
+	return env.writeConfigFile(holdsFileName, holds)
+}
+
+// Unhold releases a held version.
+func (env *Env) Unhold(v *Version) error {
+	holds, err := env.loadHolds()
+	if err != nil {
+		return err
+	}
+
+	name := v.String()
+	if installed := env.installedVersion(v); installed != nil {
+		name = installed.String()
+	}
+
+	i := slices.Index(holds, name)
+	if i < 0 {
+		return errors.New("specified version is not held")
+	}
+	holds = slices.Delete(holds, i, i+1)
+
+	return env.writeConfigFile(holdsFileName, holds)
+}
@@ -0,0 +1,44 @@
+package env
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// Hooks are shell commands run after operations on versions. The commands
+// get the version and its GOROOT in the GOSW_VERSION and GOSW_GOROOT
+// environment variables, and the env root in GOSW_ROOT.
+type Hooks struct {
+	PostInstall   string
+	PostUninstall string
+	PostSwitch    string
+}
+
+// runHook runs a hook command for v. A failure of the command is reported as
+// a warning since the operation has already been done.
+func (env *Env) runHook(name, command string, v *Version) {
+	if command == "" {
+		return
+	}
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(),
+		"GOSW_VERSION="+v.String(),
+		"GOSW_GOROOT="+env.versionGoRoot(v),
+		"GOSW_ROOT="+env.envRoot,
+	)
+
+	if err := cmd.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %s hook failed: %v\n", name, err)
+	}
+}
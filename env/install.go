@@ -7,6 +7,8 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/cheggaaa/pb/v3"
 )
@@ -20,10 +22,13 @@ func (env *Env) Install(v *Version) error {
 		return errors.New("specified version is already installed")
 	}
 
-	dlURL, dlName, err := env.downloadURL(v)
+	r, err := env.FindRelease(v)
 	if err != nil {
 		return err
 	}
+	// use the upstream name of the version, e.g. 1.20 rather than 1.20.0
+	v = r.Version.ForPlatform(v.Platform())
+	dlName := r.Filename
 
 	cachePath := filepath.Join(env.cacheDir, dlName)
 	e, err := getExtractor(cachePath)
@@ -35,18 +40,41 @@ func (env *Env) Install(v *Version) error {
 		return fmt.Errorf("failed to create cache directory: %w", err)
 	}
 
-	goRoot := env.versionGoRoot(v)
+	root := env.installRoot()
+	goRoot := filepath.Join(root, versionDirName(v))
+
+	archiveLock, err := env.lockArchive(dlName)
+	if err != nil {
+		return err
+	}
+	defer archiveLock.unlock()
 
 	if _, err := os.Stat(cachePath); err != nil {
-		if err := download(dlURL, cachePath); err != nil {
+		if err := env.downloadArchive(dlName, cachePath); err != nil {
 			return err
 		}
+	} else {
+		// update the modification time to keep the archive as recently used
+		now := time.Now()
+		if err := os.Chtimes(cachePath, now, now); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: failed to change the access and modification times: %v\n", cachePath, err)
+		}
 	}
 
+	versionLock, err := env.lockPath(goRoot)
+	if err != nil {
+		return err
+	}
+	defer versionLock.unlock()
+
 	if _, err := os.Stat(goRoot); err == nil {
 		return errors.New("install target directory already exists")
 	}
 
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return fmt.Errorf("failed to create root directory: %w", err)
+	}
+
 	if err := os.Mkdir(goRoot, 0755); err != nil {
 		return fmt.Errorf("failed to create install target directory: %w", err)
 	}
@@ -56,13 +84,74 @@ func (env *Env) Install(v *Version) error {
 		return fmt.Errorf("failed to extract archive: %w", err)
 	}
 
-	if err := env.fixBrokenLink(); err != nil {
+	if err := env.applyInstallPermissions(goRoot); err != nil {
+		return err
+	}
+
+	env.installedVersions[v.String()] = v
+	if root != env.envRoot {
+		env.systemVersions[v.String()] = true
+	}
+
+	// the first installed version becomes the current version
+	if _, err := os.Lstat(env.linkPath()); err != nil {
+		if err := env.makeLink(v); err != nil {
+			return err
+		}
+	}
+
+	if err := env.refreshLinks(); err != nil {
+		return err
+	}
+
+	if err := env.cleanCache(cachePath); err != nil {
 		return err
 	}
 
+	env.runHook("post-install", env.hooks.PostInstall, v)
+
 	return nil
 }
 
+func (env *Env) cleanCache(cachePath string) error {
+	if env.removeArchive {
+		if err := os.Remove(cachePath); err != nil {
+			return fmt.Errorf("failed to remove cached archive: %s: %w", cachePath, err)
+		}
+	}
+
+	if env.maxCacheSize > 0 {
+		if _, err := env.PruneCache(env.maxCacheSize); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// downloadArchive downloads the archive named name to path from the mirrors
+// in order, or from the official download site if no mirror is set.
+func (env *Env) downloadArchive(name, path string) error {
+	baseURLs := env.mirrors
+	if len(baseURLs) == 0 {
+		baseURLs = []string{downloadBaseURL}
+	}
+
+	var err error
+	for i, baseURL := range baseURLs {
+		if err = download(strings.TrimSuffix(baseURL, "/")+"/"+name, path); err == nil {
+			return nil
+		}
+		os.Remove(path)
+
+		if i < len(baseURLs)-1 {
+			fmt.Fprintf(os.Stderr, "warning: %s: %v\n", baseURL, err)
+		}
+	}
+
+	return err
+}
+
 func download(url, path string) error {
 	req, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
@@ -111,30 +200,56 @@ func download(url, path string) error {
 	return nil
 }
 
-func (env *Env) downloadURL(v *Version) (string, string, error) {
-	r, err := env.FindRelease(v)
-	if err != nil {
-		return "", "", err
-	}
+var ErrVersionActive = errors.New("specified version is the current version")
 
-	return downloadBaseURL + r.Filename, r.Filename, nil
-}
+// Uninstall removes an installed version. A held version or the current
+// version is not removed unless Force is specified. If the current version is
+// removed, the version link is updated by the fallback policy.
+func (env *Env) Uninstall(v *Version, opts ...RemoveOption) error {
+	o := newRemoveOptions(opts)
 
-func (env *Env) Uninstall(v *Version) error {
-	if !env.HasVersion(v) {
+	installed := env.installedVersion(v)
+	if installed == nil {
 		return errors.New("specified version is not installed")
 	}
+	v = installed
+
+	if !o.force {
+		held, err := env.IsHeld(v)
+		if err != nil {
+			return err
+		}
+		if held {
+			return ErrVersionHeld
+		}
+
+		if current, err := env.CurrentVersion(); err == nil && current.String() == v.String() {
+			return ErrVersionActive
+		}
+	}
+	if env.versionOrigin(v) == OriginSystem && !dirWritable(env.systemRoot) {
+		return fmt.Errorf("%s is installed in the shared root %s, which is not writable", v, env.systemRoot)
+	}
 	goRoot := env.versionGoRoot(v)
 
+	l, err := env.lockPath(goRoot)
+	if err != nil {
+		return err
+	}
+	defer l.unlock()
+
 	if err := os.RemoveAll(goRoot); err != nil {
 		return fmt.Errorf("failed to remove %s: %w", goRoot, err)
 	}
 
 	delete(env.installedVersions, v.String())
+	delete(env.systemVersions, v.String())
 
-	if err := env.fixBrokenLink(); err != nil {
+	if err := env.refreshLinks(); err != nil {
 		return err
 	}
 
+	env.runHook("post-uninstall", env.hooks.PostUninstall, v)
+
 	return nil
 }
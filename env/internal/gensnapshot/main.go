@@ -0,0 +1,103 @@
+// Command gensnapshot generates the snapshot of the download list bundled into
+// the env package.
+//
+// Only archives and sources of the releases of the most recent minor lines are
+// kept to keep the snapshot small.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+)
+
+const downloadListURL = "https://go.dev/dl/?mode=json&include=all"
+
+type file struct {
+	Filename       string `json:"filename"`
+	OS             string `json:"os"`
+	Arch           string `json:"arch"`
+	Version        string `json:"version"`
+	ChecksumSHA256 string `json:"sha256"`
+	Size           int64  `json:"size"`
+	Kind           string `json:"kind"`
+}
+
+type release struct {
+	Version string `json:"version"`
+	Stable  bool   `json:"stable"`
+	Files   []file `json:"files"`
+}
+
+var minorRegexp = regexp.MustCompile(`^go([0-9]+\.[0-9]+)`)
+
+func main() {
+	output := flag.String("o", "downloads_snapshot.json", "output file")
+	minors := flag.Int("minors", 4, "number of the most recent minor lines to keep")
+	flag.Parse()
+
+	if err := run(*output, *minors); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(output string, minors int) error {
+	res, err := http.Get(downloadListURL)
+	if err != nil {
+		return fmt.Errorf("failed to get download list: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to get download list: %s", res.Status)
+	}
+
+	var releases []release
+	if err := json.NewDecoder(res.Body).Decode(&releases); err != nil {
+		return fmt.Errorf("failed to decode JSON: %w", err)
+	}
+
+	// the download list is sorted in descending order
+	seen := make(map[string]bool)
+	var snapshot []release
+	for _, r := range releases {
+		m := minorRegexp.FindStringSubmatch(r.Version)
+		if m == nil {
+			continue
+		}
+		if !seen[m[1]] {
+			if len(seen) == minors {
+				break
+			}
+			seen[m[1]] = true
+		}
+
+		var files []file
+		for _, f := range r.Files {
+			if f.Kind == "archive" || f.Kind == "source" {
+				files = append(files, f)
+			}
+		}
+		r.Files = files
+
+		snapshot = append(snapshot, r)
+	}
+
+	out, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer out.Close()
+
+	e := json.NewEncoder(out)
+	e.SetIndent("", "  ")
+	if err := e.Encode(snapshot); err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+
+	return nil
+}
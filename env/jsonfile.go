@@ -0,0 +1,49 @@
+package env
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// readConfigFile decodes the JSON file in the config directory into v. v is
+// left untouched if the file does not exist.
+func (env *Env) readConfigFile(name string, v any) error {
+	file, err := os.Open(filepath.Join(env.confDir, name))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	defer file.Close()
+
+	if err := json.NewDecoder(file).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// writeConfigFile encodes v into the JSON file in the config directory.
+func (env *Env) writeConfigFile(name string, v any) error {
+	if err := os.MkdirAll(env.confDir, 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	file, err := os.Create(filepath.Join(env.confDir, name))
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", name, err)
+	}
+	defer file.Close()
+
+	e := json.NewEncoder(file)
+	e.SetIndent("", "  ")
+	if err := e.Encode(v); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,267 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const linksFileName = "links.json"
+
+var linkNameRegexp = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_.-]*$`)
+
+var ErrLinkNotFound = errors.New("link is not found")
+
+// Channel is a release channel a named link follows.
+type Channel string
+
+const (
+	// ChannelStable follows the newest installed stable version.
+	ChannelStable Channel = "stable"
+	// ChannelBeta follows the newest installed prerelease, a beta or an rc.
+	ChannelBeta Channel = "beta"
+)
+
+// ParseChannel parses the name of a release channel.
+func ParseChannel(s string) (Channel, error) {
+	switch ch := Channel(s); ch {
+	case ChannelStable, ChannelBeta:
+		return ch, nil
+	}
+
+	return "", fmt.Errorf("unknown channel: %s", s)
+}
+
+// Link is a named link in the env root other than the version link.
+type Link struct {
+	Name    string
+	Version *Version // nil if the link does not exist
+	Channel Channel  // empty if the link is pinned to a version
+}
+
+type linkConfig struct {
+	Channel Channel `json:"channel,omitempty"`
+}
+
+func (env *Env) loadLinks() (map[string]*linkConfig, error) {
+	links := make(map[string]*linkConfig)
+	if err := env.readConfigFile(linksFileName, &links); err != nil {
+		return nil, err
+	}
+
+	return links, nil
+}
+
+// validateLinkName returns an error if name cannot be used as a named link.
+// Names starting with "go" are reserved for installed versions.
+func (env *Env) validateLinkName(name string) error {
+	if !linkNameRegexp.MatchString(name) || strings.HasPrefix(name, "go") {
+		return fmt.Errorf("invalid link name: %s", name)
+	}
+	if name == env.verLinkName {
+		return fmt.Errorf("%s is the version link", name)
+	}
+
+	return nil
+}
+
+// Links returns the named links sorted by name.
+func (env *Env) Links() ([]*Link, error) {
+	links, err := env.loadLinks()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*Link, 0, len(links))
+	for _, name := range slices.Sorted(maps.Keys(links)) {
+		v, err := env.linkVersion(name)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, &Link{
+			Name:    name,
+			Version: v,
+			Channel: links[name].Channel,
+		})
+	}
+
+	return list, nil
+}
+
+// linkVersion returns the version the link named name points to, or nil if
+// the link does not exist.
+func (env *Env) linkVersion(name string) (*Version, error) {
+	path := filepath.Join(env.envRoot, name)
+	if _, err := os.Lstat(path); err != nil {
+		return nil, nil
+	}
+
+	v, err := readLinkVersion(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse version of link %s: %w", name, err)
+	}
+
+	return v, nil
+}
+
+// SetLink points the link named name to an installed version. The link stops
+// following its channel.
+func (env *Env) SetLink(name string, v *Version) error {
+	if err := env.validateLinkName(name); err != nil {
+		return err
+	}
+
+	installed := env.installedVersion(v)
+	if installed == nil {
+		return errors.New("specified version is not installed")
+	}
+
+	links, err := env.loadLinks()
+	if err != nil {
+		return err
+	}
+	links[name] = &linkConfig{}
+
+	if err := env.writeConfigFile(linksFileName, links); err != nil {
+		return err
+	}
+
+	return env.makeNamedLink(name, installed)
+}
+
+// FollowChannel makes the link named name follow a release channel, and
+// points it to the newest installed version of the channel. It returns the
+// version the link points to, or nil if no version of the channel is
+// installed.
+func (env *Env) FollowChannel(name string, ch Channel) (*Version, error) {
+	if err := env.validateLinkName(name); err != nil {
+		return nil, err
+	}
+	if _, err := ParseChannel(string(ch)); err != nil {
+		return nil, err
+	}
+
+	links, err := env.loadLinks()
+	if err != nil {
+		return nil, err
+	}
+	links[name] = &linkConfig{Channel: ch}
+
+	if err := env.writeConfigFile(linksFileName, links); err != nil {
+		return nil, err
+	}
+
+	v := env.channelVersion(ch)
+	if err := env.updateNamedLink(name, v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// RemoveLink removes the link named name.
+func (env *Env) RemoveLink(name string) error {
+	links, err := env.loadLinks()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := links[name]; !ok {
+		return ErrLinkNotFound
+	}
+	delete(links, name)
+
+	if err := env.writeConfigFile(linksFileName, links); err != nil {
+		return err
+	}
+
+	return env.removeNamedLink(name)
+}
+
+// UpdateChannelLinks points the links following channels to the newest
+// installed versions of the channels, and returns the links that are changed.
+func (env *Env) UpdateChannelLinks() ([]*Link, error) {
+	links, err := env.loadLinks()
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []*Link
+	for _, name := range slices.Sorted(maps.Keys(links)) {
+		ch := links[name].Channel
+		if ch == "" {
+			continue
+		}
+
+		current, err := env.linkVersion(name)
+		if err != nil {
+			return nil, err
+		}
+
+		v := env.channelVersion(ch)
+		if v == nil && current == nil {
+			continue
+		}
+		if v != nil && current != nil && v.String() == current.String() {
+			continue
+		}
+
+		if err := env.updateNamedLink(name, v); err != nil {
+			return nil, err
+		}
+		changed = append(changed, &Link{
+			Name:    name,
+			Version: v,
+			Channel: ch,
+		})
+	}
+
+	return changed, nil
+}
+
+// channelVersion returns the newest installed version of ch for the running
+// platform, or nil if there is none.
+func (env *Env) channelVersion(ch Channel) *Version {
+	var latest *Version
+	for _, v := range env.InstalledVersions() {
+		if v.Type == Head || v.Arch != "" {
+			continue
+		}
+		if ch == ChannelStable && v.Type != Stable {
+			continue
+		}
+		if ch == ChannelBeta && v.Type != Beta && v.Type != RC {
+			continue
+		}
+		latest = v
+	}
+
+	return latest
+}
+
+// updateNamedLink points the link named name to v, or removes it if v is nil.
+func (env *Env) updateNamedLink(name string, v *Version) error {
+	if v == nil {
+		return env.removeNamedLink(name)
+	}
+
+	return env.makeNamedLink(name, v)
+}
+
+func (env *Env) removeNamedLink(name string) error {
+	path := filepath.Join(env.envRoot, name)
+	if _, err := os.Lstat(path); err != nil {
+		return nil
+	}
+
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to remove %s symbolic link: %w", name, err)
+	}
+
+	return nil
+}
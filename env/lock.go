@@ -0,0 +1,193 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultLockTimeout is the default time to wait for a lock held by another
+// process.
+var DefaultLockTimeout = 10 * time.Minute
+
+var ErrLockTimeout = errors.New("timed out waiting for lock")
+
+const (
+	// lockDirName is the directory for the lock files, which is created in
+	// the cache directory for the cached archives, and next to the locked
+	// files otherwise.
+	lockDirName = ".locks"
+
+	lockRetryInterval = 100 * time.Millisecond
+)
+
+// fileLock is an advisory lock across processes on a lock file. The lock is
+// released by the operating system when the owner exits, so it never becomes
+// stale. The lock file is left after unlocking, because removing it would
+// let another process lock a new file while a process waiting for the old
+// one locks the removed file.
+type fileLock struct {
+	env   *Env
+	path  string
+	file  *os.File
+	count int // the number of the holders in this process
+}
+
+func (l *fileLock) unlock() {
+	l.count--
+	if l.count > 0 {
+		return
+	}
+
+	delete(l.env.heldLocks, l.path)
+	unlockFile(l.file)
+	l.file.Close()
+}
+
+// lock acquires the lock file at path. It waits for the lock held by another
+// process up to the lock timeout. A lock already held by the env is acquired
+// again without waiting, and is released when all the holders unlock it.
+func (env *Env) lock(path string) (*fileLock, error) {
+	return env.lockWithin(path, env.lockTimeout)
+}
+
+// lockWithin acquires the lock file at path, waiting for the lock held by
+// another process up to timeout. It tries only once if timeout is zero.
+func (env *Env) lockWithin(path string, timeout time.Duration) (*fileLock, error) {
+	if l, ok := env.heldLocks[path]; ok {
+		l.count++
+		return l, nil
+	}
+
+	file, err := env.openLockFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(timeout)
+	waiting := false
+	for {
+		locked, err := tryLockFile(file)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+		if locked {
+			break
+		}
+
+		if !time.Now().Before(deadline) {
+			file.Close()
+			return nil, fmt.Errorf("%w: %s is locked by %s", ErrLockTimeout, path, readLockOwner(path))
+		}
+		if !waiting {
+			fmt.Fprintf(os.Stderr, "Waiting for %s locked by %s...\n", path, readLockOwner(path))
+			waiting = true
+		}
+		time.Sleep(lockRetryInterval)
+	}
+
+	// the owner is only informative for the processes waiting for the lock
+	host, _ := os.Hostname()
+	if err := file.Truncate(0); err == nil {
+		fmt.Fprintf(file, "%d %s\n", os.Getpid(), host)
+	}
+
+	l := &fileLock{
+		env:   env,
+		path:  path,
+		file:  file,
+		count: 1,
+	}
+	if env.heldLocks == nil {
+		env.heldLocks = make(map[string]*fileLock)
+	}
+	env.heldLocks[path] = l
+
+	return l, nil
+}
+
+// openLockFile opens the lock file at path. The lock directory and the lock
+// file are created with the install permissions, so that the users sharing
+// the system root can lock them too. A lock file that is not writable is
+// opened read-only, which is enough to lock it.
+func (env *Env) openLockFile(path string) (*os.File, error) {
+	dir := filepath.Dir(path)
+	if _, err := os.Stat(dir); err != nil {
+		if err := os.MkdirAll(dir, 0777); err != nil {
+			return nil, fmt.Errorf("failed to create lock directory: %w", err)
+		}
+		if err := env.applyLockPermissions(dir, true); err != nil {
+			return nil, err
+		}
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0666)
+	if err == nil {
+		if err := env.applyLockPermissions(path, false); err != nil {
+			file.Close()
+			return nil, err
+		}
+		return file, nil
+	}
+	if !errors.Is(err, fs.ErrExist) {
+		return nil, fmt.Errorf("failed to open lock: %w", err)
+	}
+
+	file, err = os.OpenFile(path, os.O_RDWR, 0)
+	if errors.Is(err, fs.ErrPermission) {
+		file, err = os.Open(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock: %w", err)
+	}
+
+	return file, nil
+}
+
+// readLockOwner returns the description of the owner written in the lock file
+// at path.
+func readLockOwner(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "another process"
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) != 2 {
+		return "another process"
+	}
+	if _, err := strconv.Atoi(fields[0]); err != nil {
+		return "another process"
+	}
+
+	return fmt.Sprintf("process %s on %s", fields[0], fields[1])
+}
+
+// lockArchive locks the cached archive named name.
+func (env *Env) lockArchive(name string) (*fileLock, error) {
+	return env.lock(filepath.Join(env.cacheDir, lockDirName, name+".lock"))
+}
+
+// tryLockArchive locks the cached archive named name without waiting. It
+// returns nil if the archive is locked by another process, e.g. while it is
+// downloaded or extracted.
+func (env *Env) tryLockArchive(name string) (*fileLock, error) {
+	l, err := env.lockWithin(filepath.Join(env.cacheDir, lockDirName, name+".lock"), 0)
+	if errors.Is(err, ErrLockTimeout) {
+		return nil, nil
+	}
+
+	return l, err
+}
+
+// lockPath locks the file or the directory at path, e.g. a version directory
+// or a link.
+func (env *Env) lockPath(path string) (*fileLock, error) {
+	return env.lock(filepath.Join(filepath.Dir(path), lockDirName, filepath.Base(path)+".lock"))
+}
@@ -0,0 +1,185 @@
+package env
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+)
+
+const lockHelperEnv = "GOSW_TEST_LOCK_HELPER"
+
+// holdLock locks path, reports it on the standard output, and waits to be
+// killed.
+func holdLock(path string) {
+	e := &Env{lockTimeout: time.Second}
+	if _, err := e.lock(path); err != nil {
+		os.Exit(1)
+	}
+	os.Stdout.WriteString("locked\n")
+	time.Sleep(time.Minute)
+	os.Exit(1)
+}
+
+// startLockHolder starts a process holding the lock at path.
+func startLockHolder(t *testing.T, path string) *exec.Cmd {
+	t.Helper()
+
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	cmd.Env = append(os.Environ(), lockHelperEnv+"="+path)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+
+	if line, _ := bufio.NewReader(stdout).ReadString('\n'); line != "locked\n" {
+		t.Fatalf("lock holder failed to lock %s", path)
+	}
+
+	return cmd
+}
+
+func skipIfLockUnsupported(t *testing.T) {
+	t.Helper()
+
+	switch runtime.GOOS {
+	case "darwin", "dragonfly", "freebsd", "linux", "netbsd", "openbsd", "windows":
+	default:
+		t.Skipf("file locks are not supported on %s", runtime.GOOS)
+	}
+}
+
+func Test_Env_lock(t *testing.T) {
+	skipIfLockUnsupported(t)
+
+	path := filepath.Join(t.TempDir(), lockDirName, "go1.22.7.lock")
+	e := &Env{lockTimeout: 300 * time.Millisecond}
+	other := &Env{lockTimeout: 300 * time.Millisecond}
+
+	l, err := e.lock(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the env acquires its own lock again without waiting
+	l2, err := e.lock(path)
+	if err != nil {
+		t.Fatalf("lock of a file locked by the env: %v", err)
+	}
+
+	if _, err := other.lock(path); !errors.Is(err, ErrLockTimeout) {
+		t.Errorf("lock of a locked file: got %v, want %v", err, ErrLockTimeout)
+	}
+
+	// the file is locked until all the holders unlock it
+	l2.unlock()
+	if _, err := other.lock(path); !errors.Is(err, ErrLockTimeout) {
+		t.Errorf("lock of a file locked by a holder: got %v, want %v", err, ErrLockTimeout)
+	}
+	l.unlock()
+
+	l, err = other.lock(path)
+	if err != nil {
+		t.Fatalf("lock of an unlocked file: %v", err)
+	}
+	l.unlock()
+}
+
+func Test_Env_lock_timeout(t *testing.T) {
+	skipIfLockUnsupported(t)
+
+	path := filepath.Join(t.TempDir(), "go1.22.7.lock")
+	startLockHolder(t, path)
+
+	e := &Env{lockTimeout: 300 * time.Millisecond}
+	start := time.Now()
+	_, err := e.lock(path)
+	if !errors.Is(err, ErrLockTimeout) {
+		t.Fatalf("lock held by another process: got %v, want %v", err, ErrLockTimeout)
+	}
+	if elapsed := time.Since(start); elapsed < e.lockTimeout {
+		t.Errorf("lock held by another process: returned after %v, want %v or more", elapsed, e.lockTimeout)
+	}
+}
+
+func Test_Env_lock_exitedOwner(t *testing.T) {
+	skipIfLockUnsupported(t)
+
+	path := filepath.Join(t.TempDir(), "go1.22.7.lock")
+	holder := startLockHolder(t, path)
+
+	e := &Env{lockTimeout: 5 * time.Second}
+	go func() {
+		time.Sleep(300 * time.Millisecond)
+		holder.Process.Kill()
+	}()
+
+	// the lock is released when the owner is killed, and the lock file left
+	// with its process ID does not block the other processes
+	l, err := e.lock(path)
+	if err != nil {
+		t.Fatalf("lock of an exited owner: %v", err)
+	}
+	l.unlock()
+}
+
+func Test_Env_lock_leftFile(t *testing.T) {
+	skipIfLockUnsupported(t)
+
+	path := filepath.Join(t.TempDir(), "go1.22.7.lock")
+	// a lock file of a process that no longer exists
+	if err := os.WriteFile(path, []byte("999999999 localhost\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	e := &Env{lockTimeout: 300 * time.Millisecond}
+	l, err := e.lock(path)
+	if err != nil {
+		t.Fatalf("lock of a left lock file: %v", err)
+	}
+	l.unlock()
+}
+
+func Test_Env_lock_installPermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permissions are not supported on windows")
+	}
+
+	dir := filepath.Join(t.TempDir(), lockDirName)
+	path := filepath.Join(dir, "go1.22.7.lock")
+	umask := os.FileMode(0002)
+	e := &Env{
+		lockTimeout:  300 * time.Millisecond,
+		installGroup: strconv.Itoa(os.Getgid()),
+		installUmask: &umask,
+	}
+
+	l, err := e.lock(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.unlock()
+
+	// the users sharing the root can create and lock the lock files
+	for p, want := range map[string]os.FileMode{dir: 0775, path: 0664} {
+		info, err := os.Stat(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := info.Mode().Perm(); got != want {
+			t.Errorf("permissions of %s: got %v, want %v", p, got, want)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+//go:build windows
+
+package env
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// lockOffset is the offset of the locked byte, which is far beyond the owner
+// written in the lock file so that other processes can read the owner.
+const lockOffset = 1 << 30
+
+// tryLockFile locks file exclusively with LockFileEx. It returns false if the
+// file is locked by another process.
+func tryLockFile(file *os.File) (bool, error) {
+	ol := &windows.Overlapped{OffsetHigh: lockOffset}
+	err := windows.LockFileEx(windows.Handle(file.Fd()), windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY, 0, 1, 0, ol)
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, windows.ERROR_LOCK_VIOLATION):
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+func unlockFile(file *os.File) {
+	ol := &windows.Overlapped{OffsetHigh: lockOffset}
+	windows.UnlockFileEx(windows.Handle(file.Fd()), 0, 1, 0, ol)
+}
@@ -0,0 +1,302 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// MigrateOption configures the migration of an env root.
+type MigrateOption interface {
+	apply(opts *migrateOptions)
+}
+
+type migrateOptions struct {
+	copy bool
+}
+
+type migrateOptionFunc func(opts *migrateOptions)
+
+func (f migrateOptionFunc) apply(opts *migrateOptions) {
+	f(opts)
+}
+
+// Copy makes the migration copy the versions and leave the old root as it is.
+func Copy() MigrateOption {
+	return migrateOptionFunc(func(opts *migrateOptions) {
+		opts.copy = true
+	})
+}
+
+// migratedDir is a version directory migrated to another root.
+type migratedDir struct {
+	src, dst string
+	renamed  bool
+}
+
+// Migrate moves the installed versions to another env root to, and recreates
+// the version link and the named links there. The env uses to as the root
+// afterwards, and the links left in the old root are removed. The versions
+// and the version link in the old root are locked during the migration.
+//
+// A version directory is renamed if possible, or copied otherwise, e.g.
+// across file systems. If anything fails, the migrated directories are moved
+// back and the old root is left untouched. The copied directories are removed
+// from the old root only after everything succeeds, and are left with a
+// warning if they cannot be removed.
+func (env *Env) Migrate(to string, opts ...MigrateOption) error {
+	o := &migrateOptions{}
+	for _, opt := range opts {
+		opt.apply(o)
+	}
+
+	from := env.envRoot
+
+	same, err := sameDir(from, to)
+	if err != nil {
+		return err
+	}
+	if same {
+		return errors.New("cannot migrate the root to itself")
+	}
+
+	// the versions in the system root stay there
+	var versions []*Version
+	for _, v := range env.InstalledVersions() {
+		if env.versionOrigin(v) == OriginUser {
+			versions = append(versions, v)
+		}
+	}
+	if len(versions) == 0 {
+		return fmt.Errorf("no version is installed in %s", from)
+	}
+	for _, v := range versions {
+		dst := filepath.Join(to, versionDirName(v))
+		if _, err := os.Lstat(dst); err == nil {
+			return fmt.Errorf("%s already exists", dst)
+		}
+	}
+
+	// the locks are held until the migration ends, so that other processes
+	// cannot install into or switch the old root in the middle of it
+	var locks []*fileLock
+	defer func() {
+		for _, l := range locks {
+			l.unlock()
+		}
+	}()
+	paths := []string{filepath.Join(from, env.verLinkName)}
+	for _, v := range versions {
+		paths = append(paths, env.versionGoRoot(v))
+	}
+	for _, path := range paths {
+		l, err := env.lockPath(path)
+		if err != nil {
+			return err
+		}
+		locks = append(locks, l)
+	}
+
+	current, _ := env.CurrentVersion()
+
+	links, err := env.loadLinks()
+	if err != nil {
+		return err
+	}
+	pinned := make(map[string]*Version)
+	for name, link := range links {
+		if link.Channel != "" {
+			continue
+		}
+		if v, _ := env.linkVersion(name); v != nil {
+			pinned[name] = v
+		}
+	}
+
+	if err := os.MkdirAll(to, 0755); err != nil {
+		return fmt.Errorf("failed to create root directory: %w", err)
+	}
+
+	var migrated []*migratedDir
+	rollback := func() {
+		env.envRoot = from
+		for i := len(migrated) - 1; i >= 0; i-- {
+			m := migrated[i]
+			if m.renamed {
+				if err := os.Rename(m.dst, m.src); err != nil {
+					fmt.Fprintf(os.Stderr, "warning: failed to move %s back to %s: %v\n", m.dst, m.src, err)
+				}
+			} else {
+				os.RemoveAll(m.dst)
+			}
+		}
+		removeBrokenLinks(to)
+		// point the versioned commands back to the old root
+		env.refreshLinks()
+	}
+
+	for _, v := range versions {
+		m := &migratedDir{
+			src: env.versionGoRoot(v),
+			dst: filepath.Join(to, versionDirName(v)),
+		}
+
+		fmt.Printf("Migrate %s...\n", v)
+		if !o.copy && os.Rename(m.src, m.dst) == nil {
+			m.renamed = true
+		} else if err := copyDir(m.src, m.dst); err != nil {
+			os.RemoveAll(m.dst)
+			rollback()
+			return fmt.Errorf("failed to migrate %s: %w", v, err)
+		}
+		migrated = append(migrated, m)
+	}
+
+	env.envRoot = to
+
+	if err := env.migrateLinks(current, pinned); err != nil {
+		rollback()
+		return err
+	}
+
+	for _, m := range migrated {
+		if m.renamed || o.copy {
+			continue
+		}
+		if err := os.RemoveAll(m.src); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to remove %s, remove it manually: %v\n", m.src, err)
+		}
+	}
+	if !o.copy {
+		removeBrokenLinks(from)
+	}
+
+	return nil
+}
+
+// migrateLinks recreates the version link to current and the pinned named
+// links in the env root, and updates the other links.
+func (env *Env) migrateLinks(current *Version, pinned map[string]*Version) error {
+	if current != nil && env.HasVersion(current) {
+		if err := env.makeLink(current); err != nil {
+			return err
+		}
+	}
+	for name, v := range pinned {
+		if err := env.makeNamedLink(name, v); err != nil {
+			return err
+		}
+	}
+
+	return env.refreshLinks()
+}
+
+// readLinkVersion returns the version the link at path points to.
+func readLinkVersion(path string) (*Version, error) {
+	target, err := os.Readlink(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseVersion(filepath.Base(target))
+}
+
+// sameDir reports whether x and y are the same directory. Directories that do
+// not exist are compared by their absolute paths.
+func sameDir(x, y string) (bool, error) {
+	xi, xerr := os.Stat(x)
+	yi, yerr := os.Stat(y)
+	if xerr == nil && yerr == nil {
+		return os.SameFile(xi, yi), nil
+	}
+
+	ax, err := filepath.Abs(x)
+	if err != nil {
+		return false, err
+	}
+	ay, err := filepath.Abs(y)
+	if err != nil {
+		return false, err
+	}
+
+	return ax == ay, nil
+}
+
+// copyDir copies the directory tree of src to dst with the permissions and
+// the symbolic links.
+func copyDir(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case d.IsDir():
+			// the owner must be able to create the entries in the directory
+			return os.MkdirAll(target, info.Mode().Perm()|0700)
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		default:
+			return copyFile(path, target, info.Mode().Perm())
+		}
+	})
+}
+
+func copyFile(src, dst string, perm fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+
+	return out.Close()
+}
+
+// removeBrokenLinks removes the broken symbolic links in dir.
+func removeBrokenLinks(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if entry.Type()&os.ModeSymlink == 0 {
+			continue
+		}
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to remove %s: %v\n", path, err)
+		}
+	}
+}
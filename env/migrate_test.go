@@ -0,0 +1,108 @@
+package env
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// rootState returns the entries of the env root and the target of the version
+// link to compare the root before and after a migration.
+func rootState(t *testing.T, e *Env) ([]string, string) {
+	t.Helper()
+
+	var entries []string
+	err := filepath.WalkDir(e.envRoot, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(e.envRoot, path)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && rel == lockDirName {
+			return filepath.SkipDir
+		}
+		entries = append(entries, rel)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(entries)
+
+	target, _ := os.Readlink(filepath.Join(e.envRoot, e.verLinkName))
+
+	return entries, target
+}
+
+func testMigrateRollback(t *testing.T, e *Env, to string, opts ...MigrateOption) {
+	t.Helper()
+
+	from := e.envRoot
+	entries, target := rootState(t, e)
+
+	if err := e.Migrate(to, opts...); err == nil {
+		t.Fatal("Migrate: got nil, want error")
+	}
+
+	if e.envRoot != from {
+		t.Errorf("root after a failed migration: got %v, want %v", e.envRoot, from)
+	}
+	gotEntries, gotTarget := rootState(t, e)
+	if !slices.Equal(gotEntries, entries) {
+		t.Errorf("old root after a failed migration: got %v, want %v", gotEntries, entries)
+	}
+	if gotTarget != target {
+		t.Errorf("version link after a failed migration: got %v, want %v", gotTarget, target)
+	}
+
+	for _, v := range e.InstalledVersions() {
+		if _, err := os.Lstat(filepath.Join(to, versionDirName(v))); !os.IsNotExist(err) {
+			t.Errorf("%v is left in the new root: %v", v, err)
+		}
+	}
+}
+
+func Test_Env_Migrate_copyFailure(t *testing.T) {
+	e := newTestEnv(t, []string{"1.22.7", "1.23.1"})
+	v, err := ParseVersion("1.22.7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.makeLink(v); err != nil {
+		t.Fatal(err)
+	}
+
+	// a socket cannot be copied, so the copy of the second version fails
+	// after the first one is copied
+	l, err := net.Listen("unix", filepath.Join(e.envRoot, "go1.23.1", "bin", "sock"))
+	if err != nil {
+		t.Skipf("unix sockets are not supported: %v", err)
+	}
+	defer l.Close()
+
+	testMigrateRollback(t, e, filepath.Join(t.TempDir(), "to"), Copy())
+}
+
+func Test_Env_Migrate_linkFailure(t *testing.T) {
+	e := newTestEnv(t, []string{"1.22.7", "1.23.1"})
+	v, err := ParseVersion("1.22.7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.makeLink(v); err != nil {
+		t.Fatal(err)
+	}
+
+	// the version link cannot replace a directory in the new root after the
+	// versions are moved
+	to := filepath.Join(t.TempDir(), "to")
+	if err := os.MkdirAll(filepath.Join(to, e.verLinkName, "dir"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	testMigrateRollback(t, e, to)
+}
@@ -0,0 +1,84 @@
+package env
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// UpdateMinorLinks points the floating links of minor lines, such as go1.22,
+// to the newest installed stable versions of the lines for the running
+// platform, and removes the links of lines that are no longer installed. It
+// does nothing unless the minor links are enabled.
+//
+// Lines before Go 1.21 have no links, since go1.20 is the name of the first
+// release of the line, and a link of it would be taken for the release.
+func (env *Env) UpdateMinorLinks() error {
+	if !env.minorLinks {
+		return nil
+	}
+
+	latest := make(map[string]*Version)
+	for _, v := range env.InstalledVersions() {
+		if v.Type != Stable || v.Arch != "" || !v.hasPatch() || !hasMinorLink(v) {
+			continue
+		}
+		latest[minorLinkName(v)] = v
+	}
+
+	links, err := filepath.Glob(filepath.Join(env.envRoot, "/go*"))
+	if err != nil {
+		return err
+	}
+	for _, path := range links {
+		name := filepath.Base(path)
+		if _, ok := latest[name]; ok || !isMinorLink(path) {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to remove %s symbolic link: %w", name, err)
+		}
+	}
+
+	for name, v := range latest {
+		path := filepath.Join(env.envRoot, name)
+		if info, err := os.Lstat(path); err == nil && info.Mode()&os.ModeSymlink == 0 {
+			continue
+		}
+		if target, err := os.Readlink(path); err == nil && target == env.versionGoRoot(v) {
+			continue
+		}
+
+		if err := env.makeNamedLink(name, v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// hasMinorLink reports whether the minor line of v has a link, which is only
+// for Go 1.21 and later.
+func hasMinorLink(v *Version) bool {
+	return v.Major > 1 || v.Minor >= 21
+}
+
+func minorLinkName(v *Version) string {
+	return fmt.Sprintf("go%d.%d", v.Major, v.Minor)
+}
+
+// isMinorLink reports whether path is a symbolic link named after a minor
+// line.
+func isMinorLink(path string) bool {
+	info, err := os.Lstat(path)
+	if err != nil || info.Mode()&os.ModeSymlink == 0 {
+		return false
+	}
+
+	v, err := ParseVersion(info.Name())
+	if err != nil {
+		return false
+	}
+
+	return v.Type == Stable && !v.hasPatch() && v.Arch == ""
+}
@@ -0,0 +1,32 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_Env_UpdateMinorLinks(t *testing.T) {
+	e := newTestEnv(t, []string{"1.20.5", "1.22.5", "1.22.7", "1.23rc1"}, WithMinorLinks(true))
+
+	// a link of a line before Go 1.21 left by an older gosw
+	if err := os.Symlink(filepath.Join(e.envRoot, "go1.20.5"), filepath.Join(e.envRoot, "go1.20")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := e.UpdateMinorLinks(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := os.Readlink(filepath.Join(e.envRoot, "go1.22")); err != nil || got != filepath.Join(e.envRoot, "go1.22.7") {
+		t.Errorf("go1.22: got %v, %v, want %v", got, err, filepath.Join(e.envRoot, "go1.22.7"))
+	}
+
+	// go1.20 is the name of a release, which must be installable
+	if _, err := os.Lstat(filepath.Join(e.envRoot, "go1.20")); !os.IsNotExist(err) {
+		t.Errorf("go1.20: got %v, want not exist", err)
+	}
+	if _, err := os.Lstat(filepath.Join(e.envRoot, "go1.23")); !os.IsNotExist(err) {
+		t.Errorf("go1.23: got %v, want not exist", err)
+	}
+}
@@ -1,6 +1,10 @@
 package env
 
-import "path/filepath"
+import (
+	"os"
+	"path/filepath"
+	"time"
+)
 
 type Option interface {
 	apply(env *Env)
@@ -35,3 +39,113 @@ func WithCacheDir(dir string) Option {
 		env.cacheDir = dir
 	})
 }
+
+// WithMaxCacheSize sets the maximum total size of the cached archives. The
+// least recently used archives are removed after an installation to keep the
+// cache within the size. Zero means no limit.
+func WithMaxCacheSize(size int64) Option {
+	return optionFunc(func(env *Env) {
+		env.maxCacheSize = size
+	})
+}
+
+// WithRemoveArchive sets whether to remove a downloaded archive after it is
+// extracted successfully.
+func WithRemoveArchive(remove bool) Option {
+	return optionFunc(func(env *Env) {
+		env.removeArchive = remove
+	})
+}
+
+// WithReleasesTTL sets the time after which the download list is updated
+// automatically. Zero disables the automatic update by age.
+func WithReleasesTTL(ttl time.Duration) Option {
+	return optionFunc(func(env *Env) {
+		env.releasesTTL = ttl
+	})
+}
+
+// WithOffline sets whether to disable updating the download list
+// automatically.
+func WithOffline(offline bool) Option {
+	return optionFunc(func(env *Env) {
+		env.offline = offline
+	})
+}
+
+// WithMinorLinks sets whether to maintain floating links of minor lines, such
+// as go1.22 pointing to the newest installed 1.22.x.
+func WithMinorLinks(enabled bool) Option {
+	return optionFunc(func(env *Env) {
+		env.minorLinks = enabled
+	})
+}
+
+// WithCommandDir sets the directory where versioned commands such as
+// go1.22.7 and gofmt1.22.7 are linked for every installed version. Empty
+// disables the versioned commands.
+func WithCommandDir(dir string) Option {
+	return optionFunc(func(env *Env) {
+		env.commandDir = dir
+	})
+}
+
+// WithMirrors sets the base URLs from which archives are downloaded. The
+// mirrors are tried in order, and the official download site is used if no
+// mirror is set.
+func WithMirrors(urls ...string) Option {
+	return optionFunc(func(env *Env) {
+		env.mirrors = urls
+	})
+}
+
+// WithHooks sets the commands run after versions are installed, uninstalled
+// or switched.
+func WithHooks(hooks Hooks) Option {
+	return optionFunc(func(env *Env) {
+		env.hooks = hooks
+	})
+}
+
+// WithSystemRoot sets the shared system root, whose versions are available in
+// addition to the ones in the env root. Versions are installed in the system
+// root if it is writable, and the links are created in the env root.
+func WithSystemRoot(root string) Option {
+	return optionFunc(func(env *Env) {
+		if root != "" {
+			root = filepath.Clean(root)
+		}
+		env.systemRoot = root
+	})
+}
+
+// WithInstallGroup sets the group, by name or ID, of the installed files.
+func WithInstallGroup(group string) Option {
+	return optionFunc(func(env *Env) {
+		env.installGroup = group
+	})
+}
+
+// WithInstallUmask sets the umask applied to the permissions of the installed
+// files.
+func WithInstallUmask(umask os.FileMode) Option {
+	return optionFunc(func(env *Env) {
+		env.installUmask = &umask
+	})
+}
+
+// WithLockTimeout sets the time to wait for a lock on the cache or the env
+// root held by another process. Zero means not to wait.
+func WithLockTimeout(timeout time.Duration) Option {
+	return optionFunc(func(env *Env) {
+		env.lockTimeout = timeout
+	})
+}
+
+// WithFallbackPolicy sets the policy to select the version the version link
+// points to when the current version is removed.
+func WithFallbackPolicy(policy FallbackPolicy) Option {
+	return optionFunc(func(env *Env) {
+		env.fallback = policy
+	})
+}
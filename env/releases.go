@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"iter"
 	"mime"
 	"net/http"
 	"os"
@@ -13,6 +12,7 @@ import (
 	"slices"
 	"sort"
 	"strings"
+	"time"
 )
 
 const (
@@ -36,247 +36,515 @@ type remoteRelease struct {
 	Files   []remoteFile `json:"files"`
 }
 
+// Release describes a file of a release, such as an archive for a platform.
 type Release struct {
 	Version        *Version
 	Stable         bool
 	Filename       string
+	OS             string // empty for source files
+	Arch           string // upstream architecture name such as "amd64" and "armv6l", empty for source files
+	Kind           string // "archive", "installer" or "source"
 	ChecksumSHA256 string
 	Size           int64
 }
 
-func (env *Env) UpdateDownloadList() error {
+// ReleaseFilter selects releases by the platform and the kind of files. Empty
+// fields match any values.
+type ReleaseFilter struct {
+	OS   string
+	Arch string // GOARCH or upstream architecture name
+	Kind string
+}
+
+// HostReleaseFilter returns the filter that selects archives for the running
+// platform.
+func HostReleaseFilter() ReleaseFilter {
+	return ReleaseFilter{
+		OS:   runtime.GOOS,
+		Arch: runtime.GOARCH,
+		Kind: "archive",
+	}
+}
+
+// Match reports whether r is selected by the filter. Source files match any
+// platform.
+func (f ReleaseFilter) Match(r *Release) bool {
+	if f.OS != "" && r.OS != "" && r.OS != f.OS {
+		return false
+	}
+
+	if f.Arch != "" && r.Arch != "" && r.Arch != releaseArch(f.Arch) {
+		return false
+	}
+
+	if f.Kind != "" && r.Kind != f.Kind {
+		return false
+	}
+
+	return true
+}
+
+// releaseArch returns the upstream architecture name of GOARCH.
+func releaseArch(arch string) string {
+	if arch == "arm" {
+		return "armv6l"
+	}
+
+	return arch
+}
+
+// UpdateDownloadList downloads the download list and returns the changes
+// from the previous one. The changes are nil if no download list has been
+// downloaded before.
+func (env *Env) UpdateDownloadList() (*ReleaseChanges, error) {
+	meta, err := env.loadDownloadListMeta()
+	if err != nil {
+		return nil, err
+	}
+
+	old, err := env.downloadedReleases()
+	if err != nil {
+		return nil, err
+	}
+
 	req, err := http.NewRequest(http.MethodGet, downloadListURL, nil)
 	if err != nil {
-		return fmt.Errorf("failed to create http request: %w", err)
+		return nil, fmt.Errorf("failed to create http request: %w", err)
 	}
 
-	res, err := http.DefaultClient.Do(req)
+	if _, err := os.Stat(filepath.Join(env.confDir, downloadListFileName)); err == nil {
+		if meta.ETag != "" {
+			req.Header.Set("If-None-Match", meta.ETag)
+		}
+		if meta.LastModified != "" {
+			req.Header.Set("If-Modified-Since", meta.LastModified)
+		}
+	}
+
+	res, err := downloadListClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to get download list: %w", err)
+		return nil, fmt.Errorf("failed to get download list: %w", err)
 	}
 	defer res.Body.Close()
 
-	if res.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to get download list: %s", res.Status)
+	switch res.StatusCode {
+	case http.StatusOK:
+		// ok
+	case http.StatusNotModified:
+		meta.FetchedAt = time.Now()
+		meta.FailedAt = time.Time{}
+		if err := env.saveDownloadListMeta(meta); err != nil {
+			return nil, err
+		}
+		env.refreshed = true
+
+		if env.releases == nil || env.fromSnapshot {
+			if err := env.loadReleases(); err != nil {
+				return nil, err
+			}
+		}
+
+		return &ReleaseChanges{}, nil
+	default:
+		return nil, fmt.Errorf("failed to get download list: %s", res.Status)
 	}
 
 	mimeType, _, err := mime.ParseMediaType(res.Header.Get("Content-Type"))
 	if err != nil {
-		return fmt.Errorf("failed to parse Content-Type: %w", err)
+		return nil, fmt.Errorf("failed to parse Content-Type: %w", err)
 	}
 
 	if mimeType != "application/json" {
-		return fmt.Errorf("the server responds unexpected Content-Type: %s", mimeType)
+		return nil, fmt.Errorf("the server responds unexpected Content-Type: %s", mimeType)
 	}
 
 	var releases []remoteRelease
 	if err := json.NewDecoder(res.Body).Decode(&releases); err != nil {
-		return fmt.Errorf("failed to decode JSON: %w", err)
+		return nil, fmt.Errorf("failed to decode JSON: %w", err)
 	}
 
-	rls, err := convertReleases(releases)
-	if err != nil {
-		return err
-	}
-	env.releases = rls
+	env.releases, env.releaseWarnings = convertReleases(releases)
+	env.fromSnapshot = false
 
 	if err := os.MkdirAll(env.confDir, 0755); err != nil {
-		return fmt.Errorf("failed to create config directory: %w", err)
+		return nil, fmt.Errorf("failed to create config directory: %w", err)
 	}
 
 	file, err := os.Create(filepath.Join(env.confDir, downloadListFileName))
 	if err != nil {
-		return fmt.Errorf("failed to create download list file: %w", err)
+		return nil, fmt.Errorf("failed to create download list file: %w", err)
 	}
 	defer file.Close()
 
 	e := json.NewEncoder(file)
 	e.SetIndent("", "  ")
 	if err := e.Encode(releases); err != nil {
-		return fmt.Errorf("failed to encode JSON: %w", err)
+		return nil, fmt.Errorf("failed to encode JSON: %w", err)
 	}
 
-	return nil
+	err = env.saveDownloadListMeta(&downloadListMeta{
+		FetchedAt:    time.Now(),
+		ETag:         res.Header.Get("ETag"),
+		LastModified: res.Header.Get("Last-Modified"),
+	})
+	if err != nil {
+		return nil, err
+	}
+	env.refreshed = true
+
+	if old == nil {
+		return nil, nil
+	}
+
+	return diffReleases(old, env.releases), nil
 }
 
-func convertReleases(releases []remoteRelease) ([]*Release, error) {
+// convertReleases converts the releases in the download list. Releases whose
+// versions cannot be parsed are skipped and reported as warnings.
+func convertReleases(releases []remoteRelease) ([]*Release, []*Warning) {
 	var rls []*Release
+	var warnings []*Warning
 
 	for _, r := range releases {
-		for _, f := range r.Files {
-			if !targetRelease(f) {
-				continue
-			}
-
-			version, err := ParseVersion(strings.TrimPrefix(r.Version, "go"))
-			if err != nil {
-				return nil, err
-			}
+		version, err := ParseVersion(strings.TrimPrefix(r.Version, "go"))
+		if err != nil {
+			warnings = append(warnings, &Warning{
+				Source: downloadListFileName,
+				Name:   r.Version,
+				Err:    err,
+			})
+			continue
+		}
 
+		for _, f := range r.Files {
 			rls = append(rls, &Release{
 				Version:        version,
 				Stable:         r.Stable,
 				Filename:       f.Filename,
+				OS:             f.OS,
+				Arch:           f.Arch,
+				Kind:           f.Kind,
 				ChecksumSHA256: f.ChecksumSHA256,
 				Size:           f.Size,
 			})
 		}
 	}
 
-	sort.Slice(rls, func(i, j int) bool {
+	sort.SliceStable(rls, func(i, j int) bool {
 		return CompareVersion(rls[i].Version, rls[j].Version) < 0
 	})
 
-	return rls, nil
+	return rls, warnings
 }
 
-func targetRelease(f remoteFile) bool {
-	if f.OS != runtime.GOOS || f.Kind != "archive" {
-		return false
+var ErrReleasesFileNotDownloaded = errors.New("releases file is not found")
+
+// DownloadListUpdatedAt returns the time the download list was last fetched.
+func (env *Env) DownloadListUpdatedAt() (time.Time, error) {
+	info, err := os.Stat(filepath.Join(env.confDir, downloadListFileName))
+	if err != nil {
+		return time.Time{}, ErrReleasesFileNotDownloaded
 	}
 
-	arch := runtime.GOARCH
-	if arch == "arm" {
-		arch = "armv6l"
+	meta, err := env.loadDownloadListMeta()
+	if err != nil {
+		return time.Time{}, err
 	}
 
-	if f.Arch != arch {
-		return false
+	if meta.FetchedAt.IsZero() {
+		return info.ModTime(), nil
 	}
 
-	return true
+	return meta.FetchedAt, nil
 }
 
-var ErrReleasesFileNotDownloaded = errors.New("releases file is not found")
+// ensureReleases loads the releases if they are not loaded yet. The download
+// list is updated before loading if it is not downloaded or older than the
+// TTL, unless the env is offline or the update failed recently.
+func (env *Env) ensureReleases() error {
+	if env.releases != nil {
+		return nil
+	}
 
-func (env *Env) loadReleases() error {
-	name := filepath.Join(env.confDir, downloadListFileName)
-	if _, err := os.Stat(name); err != nil {
-		return ErrReleasesFileNotDownloaded
+	if env.offline || !env.downloadListStale() || env.updateFailedRecently() {
+		return env.loadReleases()
 	}
 
-	file, err := os.Open(name)
+	if err := env.autoUpdateDownloadList(); err != nil {
+		if loadErr := env.loadReleases(); loadErr != nil {
+			return err
+		}
+
+		env.autoUpdateErr = err
+	}
+
+	return nil
+}
+
+func (env *Env) downloadListStale() bool {
+	updatedAt, err := env.DownloadListUpdatedAt()
 	if err != nil {
-		return fmt.Errorf("failed to open releases file: %w", err)
+		return true
 	}
-	defer file.Close()
 
-	var releases []remoteRelease
-	if err := json.NewDecoder(file).Decode(&releases); err != nil {
-		return fmt.Errorf("failed to decode JSON: %w", err)
+	return env.releasesTTL > 0 && time.Since(updatedAt) > env.releasesTTL
+}
+
+func (env *Env) loadReleases() error {
+	name := filepath.Join(env.confDir, downloadListFileName)
+	if _, err := os.Stat(name); err != nil {
+		return env.loadSnapshotReleases()
 	}
 
-	rls, err := convertReleases(releases)
+	releases, err := readDownloadList(name)
 	if err != nil {
 		return err
 	}
-	env.releases = rls
+
+	env.releases, env.releaseWarnings = convertReleases(releases)
 
 	return nil
 }
 
-func (env *Env) Releases() ([]*Release, error) {
-	if env.releases == nil {
-		if err := env.loadReleases(); err != nil {
-			return nil, err
-		}
+// storedReleases returns the loaded releases, or the releases of the
+// downloaded download list or the bundled snapshot, without updating the
+// download list.
+func (env *Env) storedReleases() ([]*Release, error) {
+	if env.releases != nil {
+		return env.releases, nil
 	}
 
-	if len(env.releases) == 0 {
-		return nil, nil
+	releases, err := env.downloadedReleases()
+	if err != nil || releases != nil {
+		return releases, err
 	}
 
-	return slices.Clone(env.releases), nil
+	releases, _, err = snapshotReleases()
+
+	return releases, err
 }
 
-func (env *Env) RecentReleases() ([]*Release, error) {
-	if env.releases == nil {
-		if err := env.loadReleases(); err != nil {
-			return nil, err
-		}
+// downloadedReleases returns the releases of the downloaded download list,
+// or nil if it is not downloaded.
+func (env *Env) downloadedReleases() ([]*Release, error) {
+	if env.releases != nil && !env.fromSnapshot {
+		return env.releases, nil
 	}
 
-	if len(env.releases) == 0 {
+	name := filepath.Join(env.confDir, downloadListFileName)
+	if _, err := os.Stat(name); err != nil {
 		return nil, nil
 	}
 
-	releases := slices.Collect(env.selectRecentReleases(2))
-	slices.Reverse(releases)
+	releases, err := readDownloadList(name)
+	if err != nil {
+		return nil, err
+	}
+
+	rls, _ := convertReleases(releases)
+
+	return rls, nil
+}
+
+func readDownloadList(name string) ([]remoteRelease, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open releases file: %w", err)
+	}
+	defer file.Close()
+
+	var releases []remoteRelease
+	if err := json.NewDecoder(file).Decode(&releases); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON: %w", err)
+	}
 
 	return releases, nil
 }
 
-func (env *Env) selectRecentReleases(n int) iter.Seq[*Release] {
-	return func(yield func(*Release) bool) {
-		var latest *Version
-		// add the latest unstable release first
-		for _, r := range slices.Backward(env.releases) {
-			if r.Stable {
-				break
-			}
+// Releases returns the archives for the running platform in ascending order.
+func (env *Env) Releases() ([]*Release, error) {
+	return env.FilterReleases(HostReleaseFilter())
+}
 
-			if latest == nil {
-				latest = &Version{
-					Major: r.Version.Major,
-					Minor: r.Version.Minor,
-				}
-			}
+// FilterReleases returns the releases selected by f in ascending order.
+func (env *Env) FilterReleases(f ReleaseFilter) ([]*Release, error) {
+	if err := env.ensureReleases(); err != nil {
+		return nil, err
+	}
 
-			if r.Version.Major != latest.Major || r.Version.Minor != latest.Minor {
-				break
-			}
+	return filterReleases(env.releases, f), nil
+}
+
+func filterReleases(releases []*Release, f ReleaseFilter) []*Release {
+	var filtered []*Release
+	for _, r := range releases {
+		if f.Match(r) {
+			filtered = append(filtered, r)
+		}
+	}
+
+	return filtered
+}
+
+// RecentOptions configures the selection of recent releases.
+type RecentOptions struct {
+	// Minors is the number of the most recent stable minor lines to select.
+	// Zero selects the supported minor lines.
+	Minors int
+	// Patches is the number of the most recent patch releases to select in
+	// each minor line. Zero selects all of them.
+	Patches int
+	// Since selects every minor line since the minor line of Since instead of
+	// Minors if it is not nil.
+	Since *Version
+	// StableOnly excludes prereleases.
+	StableOnly bool
+	// AllPrereleases selects prereleases of every selected minor line in
+	// addition to the prereleases of the upcoming minor line.
+	AllPrereleases bool
+	// Filter selects the files of releases. The zero value selects every file.
+	Filter ReleaseFilter
+}
 
-			if !yield(r) {
-				return
+// DefaultRecentOptions returns the options to select the two most recent
+// patch releases of the supported minor lines and the prereleases of the
+// upcoming minor line for the running platform.
+func DefaultRecentOptions() *RecentOptions {
+	return &RecentOptions{
+		Patches: 2,
+		Filter:  HostReleaseFilter(),
+	}
+}
+
+// RecentReleases returns the recent releases selected by opts in ascending
+// order. If opts is nil, DefaultRecentOptions is used.
+func (env *Env) RecentReleases(opts *RecentOptions) ([]*Release, error) {
+	if opts == nil {
+		opts = DefaultRecentOptions()
+	}
+
+	releases, err := env.FilterReleases(opts.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(releases) == 0 {
+		return nil, nil
+	}
+
+	return selectRecentReleases(releases, opts), nil
+}
+
+// selectRecentReleases selects the recent releases from releases, which must
+// be sorted in ascending order.
+func selectRecentReleases(releases []*Release, opts *RecentOptions) []*Release {
+	// minor lines of stable releases in descending order
+	var lines []*Version
+	for _, r := range slices.Backward(releases) {
+		if !r.Stable {
+			continue
+		}
+		if n := len(lines); n > 0 && EqualMinorVersion(lines[n-1], r.Version) {
+			continue
+		}
+		lines = append(lines, r.Version)
+	}
+
+	var selectedLines []*Version
+	switch {
+	case opts.Since != nil:
+		for _, l := range lines {
+			if compareMinorVersion(l, opts.Since) >= 0 {
+				selectedLines = append(selectedLines, l)
 			}
 		}
+	case opts.Minors > 0:
+		selectedLines = lines[:min(opts.Minors, len(lines))]
+	default:
+		selectedLines = lines[:min(supportedMinorLines, len(lines))]
+	}
+	selectedLine := func(v *Version) bool {
+		return slices.ContainsFunc(selectedLines, func(l *Version) bool {
+			return EqualMinorVersion(l, v)
+		})
+	}
+
+	// the upcoming minor line, which has only prereleases
+	var upcoming *Version
+	if last := releases[len(releases)-1]; !last.Stable && (len(lines) == 0 || compareMinorVersion(last.Version, lines[0]) > 0) {
+		upcoming = last.Version
+	}
 
-		latest = nil
-		count := 0
-		// add the latest stable releases second
-		for _, r := range slices.Backward(env.releases) {
-			if !r.Stable {
+	var selected []*Release
+	var last *Version
+	count := 0
+	for _, r := range slices.Backward(releases) {
+		if !r.Stable {
+			if opts.StableOnly {
 				continue
 			}
 
-			if latest == nil || r.Version.Major != latest.Major || r.Version.Minor != latest.Minor {
-				if latest != nil {
-					n--
-				}
-				if n == 0 {
-					break
-				}
-				count = 0
-
-				latest = &Version{
-					Major: r.Version.Major,
-					Minor: r.Version.Minor,
-				}
+			if (upcoming != nil && EqualMinorVersion(r.Version, upcoming)) || (opts.AllPrereleases && selectedLine(r.Version)) {
+				selected = append(selected, r)
 			}
+			continue
+		}
 
-			if count < 2 {
-				if !yield(r) {
-					return
-				}
-			}
+		if !selectedLine(r.Version) {
+			continue
+		}
+
+		// count versions rather than files
+		if last == nil || !EqualMinorVersion(last, r.Version) {
+			count = 1
+			last = r.Version
+		} else if !EqualVersion(last, r.Version) {
 			count++
+			last = r.Version
+		}
+
+		if opts.Patches == 0 || count <= opts.Patches {
+			selected = append(selected, r)
 		}
 	}
+	slices.Reverse(selected)
 
+	return selected
 }
 
+// FindRelease returns the archive of v for the platform of v.
 func (env *Env) FindRelease(v *Version) (*Release, error) {
-	if env.releases == nil {
-		if err := env.loadReleases(); err != nil {
-			return nil, err
-		}
+	if err := env.ensureReleases(); err != nil {
+		return nil, err
 	}
 
-	for _, r := range env.releases {
-		if EqualVersion(r.Version, v) {
+	f := v.releaseFilter()
+	if r := findRelease(filterReleases(env.releases, f), v); r != nil {
+		return r, nil
+	}
+
+	// the version may be released after the download list was updated
+	if !env.offline && !env.refreshed && !env.updateFailedRecently() {
+		if err := env.autoUpdateDownloadList(); err != nil {
+			env.autoUpdateErr = err
+		} else if r := findRelease(filterReleases(env.releases, f), v); r != nil {
 			return r, nil
 		}
 	}
 
+	if v.IsLang() {
+		return nil, fmt.Errorf("specified version is not found: %s is a language version, the first release is %s.0", v, v)
+	}
+
 	return nil, errors.New("specified version is not found")
 }
+
+func findRelease(releases []*Release, v *Version) *Release {
+	for _, r := range releases {
+		if EqualVersion(r.Version, v) {
+			return r
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package env
+
+import (
+	"slices"
+	"testing"
+)
+
+var releaseFilterTests = map[string]struct {
+	f    ReleaseFilter
+	r    *Release
+	want bool
+}{
+	"match": {
+		f:    ReleaseFilter{OS: "linux", Arch: "amd64", Kind: "archive"},
+		r:    &Release{OS: "linux", Arch: "amd64", Kind: "archive"},
+		want: true,
+	},
+	"any": {
+		f:    ReleaseFilter{},
+		r:    &Release{OS: "windows", Arch: "arm64", Kind: "installer"},
+		want: true,
+	},
+	"os mismatch": {
+		f:    ReleaseFilter{OS: "linux", Arch: "amd64", Kind: "archive"},
+		r:    &Release{OS: "darwin", Arch: "amd64", Kind: "archive"},
+		want: false,
+	},
+	"arch mismatch": {
+		f:    ReleaseFilter{OS: "linux", Arch: "amd64", Kind: "archive"},
+		r:    &Release{OS: "linux", Arch: "386", Kind: "archive"},
+		want: false,
+	},
+	"kind mismatch": {
+		f:    ReleaseFilter{OS: "linux", Arch: "amd64", Kind: "archive"},
+		r:    &Release{OS: "linux", Arch: "amd64", Kind: "installer"},
+		want: false,
+	},
+	"goarch arm": {
+		f:    ReleaseFilter{OS: "linux", Arch: "arm", Kind: "archive"},
+		r:    &Release{OS: "linux", Arch: "armv6l", Kind: "archive"},
+		want: true,
+	},
+	"source": {
+		f:    ReleaseFilter{OS: "linux", Arch: "amd64", Kind: "source"},
+		r:    &Release{Kind: "source"},
+		want: true,
+	},
+}
+
+func Test_ReleaseFilter_Match(t *testing.T) {
+	for name, tt := range releaseFilterTests {
+		t.Run(name, func(t *testing.T) {
+			if got := tt.f.Match(tt.r); got != tt.want {
+				t.Errorf("Match(%+v): got %v, want %v", tt.r, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_convertReleases(t *testing.T) {
+	releases := []remoteRelease{
+		{
+			Version: "go1.22.7",
+			Stable:  true,
+			Files: []remoteFile{
+				{Filename: "go1.22.7.linux-amd64.tar.gz", OS: "linux", Arch: "amd64", Kind: "archive"},
+				{Filename: "go1.22.7.src.tar.gz", Kind: "source"},
+			},
+		},
+		{
+			Version: "go1.23.0+auto",
+			Stable:  true,
+			Files: []remoteFile{
+				{Filename: "go1.23.0+auto.linux-amd64.tar.gz", OS: "linux", Arch: "amd64", Kind: "archive"},
+			},
+		},
+	}
+
+	rls, warnings := convertReleases(releases)
+	if len(rls) != 2 {
+		t.Errorf("convertReleases: got %d releases, want 2", len(rls))
+	}
+	if len(warnings) != 1 || warnings[0].Name != "go1.23.0+auto" {
+		t.Errorf("convertReleases: got warnings %v, want a warning for go1.23.0+auto", warnings)
+	}
+}
+
+var recentReleasesTests = map[string]struct {
+	opts *RecentOptions
+	want []string
+}{
+	"default": {
+		opts: &RecentOptions{Patches: 2},
+		want: []string{"1.22.6", "1.22.7", "1.23.1", "1.23.2", "1.24rc1"},
+	},
+	"minors": {
+		opts: &RecentOptions{Minors: 1, Patches: 0},
+		want: []string{"1.23.0", "1.23.1", "1.23.2", "1.24rc1"},
+	},
+	"since": {
+		opts: &RecentOptions{Since: &Version{Major: 1, Minor: 21}, Patches: 1},
+		want: []string{"1.21.13", "1.22.7", "1.23.2", "1.24rc1"},
+	},
+	"stable only": {
+		opts: &RecentOptions{Minors: 1, Patches: 1, StableOnly: true},
+		want: []string{"1.23.2"},
+	},
+	"all prereleases": {
+		opts: &RecentOptions{Minors: 1, Patches: 1, AllPrereleases: true},
+		want: []string{"1.23rc1", "1.23.2", "1.24rc1"},
+	},
+}
+
+func Test_selectRecentReleases(t *testing.T) {
+	var releases []*Release
+	for _, s := range []string{"1.21.12", "1.21.13", "1.22rc1", "1.22.6", "1.22.7", "1.23rc1", "1.23.0", "1.23.1", "1.23.2", "1.24rc1"} {
+		v, err := ParseVersion(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		releases = append(releases, &Release{Version: v, Stable: v.Type == Stable})
+	}
+
+	for name, tt := range recentReleasesTests {
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			for _, r := range selectRecentReleases(releases, tt.opts) {
+				got = append(got, r.Version.String())
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("selectRecentReleases: got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package env
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+)
+
+// MatchVersions returns the installed versions matching v in ascending order.
+// A stable version without a patch such as 1.20 matches every installed
+// version of the minor line for the same platform, and the other versions
+// match the same installed version.
+func (env *Env) MatchVersions(v *Version) ([]*Version, error) {
+	if v.Type == Stable && !v.hasPatch() {
+		var matched []*Version
+		for _, installed := range env.InstalledVersions() {
+			if EqualMinorVersion(installed, v) && SamePlatform(installed, v) {
+				matched = append(matched, installed)
+			}
+		}
+		if len(matched) == 0 {
+			return nil, fmt.Errorf("no installed version matches %s", v)
+		}
+		return matched, nil
+	}
+
+	installed := env.installedVersion(v)
+	if installed == nil {
+		return nil, fmt.Errorf("%s is not installed", v)
+	}
+
+	return []*Version{installed}, nil
+}
+
+// InstalledSize returns the total size of the files of an installed version.
+func (env *Env) InstalledSize(v *Version) (int64, error) {
+	installed := env.installedVersion(v)
+	if installed == nil {
+		return 0, fmt.Errorf("%s is not installed", v)
+	}
+
+	var size int64
+	err := filepath.WalkDir(env.versionGoRoot(installed), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get size of %s: %w", v, err)
+	}
+
+	return size, nil
+}
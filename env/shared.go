@@ -0,0 +1,178 @@
+package env
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+)
+
+// Origin is the root where a version is installed.
+type Origin int
+
+const (
+	// OriginUser is the env root, which is per-user when the system root is
+	// shared.
+	OriginUser Origin = iota
+	// OriginSystem is the shared system root.
+	OriginSystem
+)
+
+func (o Origin) String() string {
+	switch o {
+	case OriginUser:
+		return "user"
+	case OriginSystem:
+		return "system"
+	}
+
+	return ""
+}
+
+// Installation is an installed version with the root where it is installed.
+type Installation struct {
+	Version *Version
+	Origin  Origin
+	GoRoot  string
+}
+
+// Installations returns the versions installed in the env root and the system
+// root in ascending order. A version installed in both roots is the one in
+// the env root.
+func (env *Env) Installations() []*Installation {
+	versions := env.InstalledVersions()
+
+	installations := make([]*Installation, 0, len(versions))
+	for _, v := range versions {
+		installations = append(installations, &Installation{
+			Version: v,
+			Origin:  env.versionOrigin(v),
+			GoRoot:  env.versionGoRoot(v),
+		})
+	}
+
+	return installations
+}
+
+func (env *Env) versionOrigin(v *Version) Origin {
+	if env.systemVersions[v.String()] {
+		return OriginSystem
+	}
+
+	return OriginUser
+}
+
+// installRoot returns the root where versions are installed, which is the
+// system root if it is writable, or the env root otherwise.
+func (env *Env) installRoot() string {
+	if env.systemRoot != "" && dirWritable(env.systemRoot) {
+		return env.systemRoot
+	}
+
+	return env.envRoot
+}
+
+// dirWritable reports whether a file can be created in dir.
+func dirWritable(dir string) bool {
+	file, err := os.CreateTemp(dir, ".gosw-")
+	if err != nil {
+		return false
+	}
+	file.Close()
+	os.Remove(file.Name())
+
+	return true
+}
+
+// applyInstallPermissions changes the group and the permissions of the files
+// in goRoot as configured. Directories and executables get 0777 and the other
+// files get 0666 masked with the umask.
+func (env *Env) applyInstallPermissions(goRoot string) error {
+	if env.installGroup == "" && env.installUmask == nil {
+		return nil
+	}
+
+	gid := -1
+	if env.installGroup != "" {
+		g, err := lookupGroup(env.installGroup)
+		if err != nil {
+			return err
+		}
+		gid = g
+	}
+
+	return filepath.WalkDir(goRoot, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if gid >= 0 {
+			if err := os.Lchown(path, -1, gid); err != nil {
+				return fmt.Errorf("failed to change group: %w", err)
+			}
+		}
+
+		if env.installUmask == nil || d.Type()&os.ModeSymlink != 0 {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		perm := fs.FileMode(0666)
+		if d.IsDir() || info.Mode().Perm()&0111 != 0 {
+			perm = 0777
+		}
+		if err := os.Chmod(path, perm&^*env.installUmask); err != nil {
+			return fmt.Errorf("failed to change permissions: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// applyLockPermissions changes the group and the permissions of a lock file or
+// a lock directory as configured, in the same way as applyInstallPermissions.
+func (env *Env) applyLockPermissions(path string, dir bool) error {
+	if env.installGroup != "" {
+		gid, err := lookupGroup(env.installGroup)
+		if err != nil {
+			return err
+		}
+		if err := os.Lchown(path, -1, gid); err != nil {
+			return fmt.Errorf("failed to change group of lock: %w", err)
+		}
+	}
+
+	if env.installUmask != nil {
+		perm := fs.FileMode(0666)
+		if dir {
+			perm = 0777
+		}
+		if err := os.Chmod(path, perm&^*env.installUmask); err != nil {
+			return fmt.Errorf("failed to change permissions of lock: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// lookupGroup returns the ID of a group specified by the name or the ID.
+func lookupGroup(group string) (int, error) {
+	g, err := user.LookupGroup(group)
+	if err != nil {
+		if g, err = user.LookupGroupId(group); err != nil {
+			return 0, fmt.Errorf("unknown group: %s", group)
+		}
+	}
+
+	gid, err := strconv.Atoi(g.Gid)
+	if err != nil {
+		return 0, fmt.Errorf("unsupported group ID: %s", g.Gid)
+	}
+
+	return gid, nil
+}
@@ -0,0 +1,45 @@
+package env
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+)
+
+// snapshotDownloadList is a snapshot of the download list bundled into the
+// binary. It is used until the download list is downloaded, so it may be
+// stale.
+//
+//go:generate go run ./internal/gensnapshot -o downloads_snapshot.json
+//go:embed downloads_snapshot.json
+var snapshotDownloadList []byte
+
+func (env *Env) loadSnapshotReleases() error {
+	releases, warnings, err := snapshotReleases()
+	if err != nil {
+		return err
+	}
+
+	env.releases, env.releaseWarnings = releases, warnings
+	env.fromSnapshot = true
+
+	return nil
+}
+
+func snapshotReleases() ([]*Release, []*Warning, error) {
+	var releases []remoteRelease
+	if err := json.Unmarshal(snapshotDownloadList, &releases); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode bundled releases: %w", err)
+	}
+
+	rls, warnings := convertReleases(releases)
+
+	return rls, warnings, nil
+}
+
+// UsingSnapshot reports whether the releases are loaded from the snapshot
+// bundled into the binary, which may be stale, because the download list is
+// not downloaded yet.
+func (env *Env) UsingSnapshot() bool {
+	return env.fromSnapshot
+}
@@ -0,0 +1,49 @@
+package env
+
+// VersionStatus describes the patch and support status of an installed
+// version.
+type VersionStatus struct {
+	Version   *Version
+	Latest    *Version // the newest stable release of the minor line, nil if there is none
+	Supported bool     // whether the minor line is still in the support window
+	Current   bool     // whether the version is the current version
+}
+
+// Outdated reports whether a newer patch release of the minor line exists.
+func (s *VersionStatus) Outdated() bool {
+	return s.Latest != nil && CompareVersion(s.Latest, s.Version) > 0
+}
+
+// VersionStatuses returns the status of every installed version in ascending
+// order.
+func (env *Env) VersionStatuses() ([]*VersionStatus, error) {
+	releases, err := env.FilterReleases(ReleaseFilter{Kind: "archive"})
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := env.CurrentVersion()
+	if err != nil && err != ErrNoCurrentVersion {
+		return nil, err
+	}
+
+	window := supportedLines(releases)
+
+	versions := env.InstalledVersions()
+	statuses := make([]*VersionStatus, 0, len(versions))
+	for _, v := range versions {
+		s := &VersionStatus{
+			Version:   v,
+			Supported: true,
+			Current:   current != nil && EqualVersion(current, v) && SamePlatform(current, v),
+		}
+		if v.Type != Head {
+			s.Latest = latestStableRelease(filterReleases(releases, v.releaseFilter()), v)
+			s.Supported = window.Supports(v)
+		}
+
+		statuses = append(statuses, s)
+	}
+
+	return statuses, nil
+}
@@ -0,0 +1,72 @@
+package env
+
+// supportedMinorLines is the number of the most recent stable minor lines that
+// are supported by the Go team.
+const supportedMinorLines = 2
+
+// SupportWindow is the minor lines supported by the Go team in descending
+// order, such as 1.23 and 1.22. Only Major and Minor of the versions are set.
+type SupportWindow []*Version
+
+// Supports reports whether the minor line of v is in the support window or
+// newer than it, e.g. a prerelease of the next minor line.
+func (w SupportWindow) Supports(v *Version) bool {
+	if v.Type == Head || len(w) == 0 {
+		return true
+	}
+
+	return compareMinorVersion(v, w[len(w)-1]) >= 0
+}
+
+// SupportWindow returns the support window computed from the releases. It
+// does not update the download list, so that commands reading only the local
+// state never access the network.
+func (env *Env) SupportWindow() (SupportWindow, error) {
+	releases, err := env.storedReleases()
+	if err != nil {
+		return nil, err
+	}
+
+	return supportedLines(filterReleases(releases, HostReleaseFilter())), nil
+}
+
+// IsSupported reports whether the minor line of v is supported.
+func (env *Env) IsSupported(v *Version) (bool, error) {
+	w, err := env.SupportWindow()
+	if err != nil {
+		return false, err
+	}
+
+	return w.Supports(v), nil
+}
+
+// supportedLines returns the most recent stable minor lines of releases, which
+// must be sorted in ascending order.
+func supportedLines(releases []*Release) SupportWindow {
+	var lines SupportWindow
+	for i := len(releases) - 1; i >= 0 && len(lines) < supportedMinorLines; i-- {
+		r := releases[i]
+		if !r.Stable || r.Version.Type != Stable {
+			continue
+		}
+
+		if n := len(lines); n > 0 && EqualMinorVersion(lines[n-1], r.Version) {
+			continue
+		}
+
+		lines = append(lines, &Version{
+			Major: r.Version.Major,
+			Minor: r.Version.Minor,
+		})
+	}
+
+	return lines
+}
+
+func compareMinorVersion(x, y *Version) int {
+	if x.Major != y.Major {
+		return compareInt(x.Major, y.Major)
+	}
+
+	return compareInt(x.Minor, y.Minor)
+}
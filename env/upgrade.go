@@ -0,0 +1,79 @@
+package env
+
+import "slices"
+
+// Upgrade describes a minor line whose newest installed version has a newer
+// patch release available.
+type Upgrade struct {
+	From *Version // the newest installed version of the minor line
+	To   *Version // the newest stable release of the minor line
+}
+
+// Upgrades returns the upgrades available for every minor line that has at
+// least one installed version, in ascending order of the minor lines. Minor
+// lines are upgraded separately for each platform.
+func (env *Env) Upgrades() ([]*Upgrade, error) {
+	releases, err := env.FilterReleases(ReleaseFilter{Kind: "archive"})
+	if err != nil {
+		return nil, err
+	}
+
+	var upgrades []*Upgrade
+	for _, installed := range latestInstalledLines(env.InstalledVersions()) {
+		latest := latestStableRelease(filterReleases(releases, installed.releaseFilter()), installed)
+		if latest == nil || CompareVersion(latest, installed) <= 0 {
+			continue
+		}
+		latest = latest.ForPlatform(installed.Platform())
+		if env.HasVersion(latest) {
+			continue
+		}
+
+		upgrades = append(upgrades, &Upgrade{
+			From: installed,
+			To:   latest,
+		})
+	}
+
+	return upgrades, nil
+}
+
+// latestInstalledLines returns the newest version of each minor line and
+// platform in versions, which must be sorted in ascending order.
+func latestInstalledLines(versions []*Version) []*Version {
+	var latest []*Version
+	for _, v := range versions {
+		if v.Type == Head {
+			continue
+		}
+
+		i := slices.IndexFunc(latest, func(l *Version) bool {
+			return EqualMinorVersion(l, v) && SamePlatform(l, v)
+		})
+		if i >= 0 {
+			latest[i] = v
+			continue
+		}
+
+		latest = append(latest, v)
+	}
+
+	return latest
+}
+
+// latestStableRelease returns the newest stable release of the minor line of
+// v, or nil if the line has no stable release.
+func latestStableRelease(releases []*Release, v *Version) *Version {
+	var latest *Version
+	for _, r := range releases {
+		if !r.Stable || r.Version.Type != Stable || !EqualMinorVersion(r.Version, v) {
+			continue
+		}
+
+		if latest == nil || CompareVersion(r.Version, latest) > 0 {
+			latest = r.Version
+		}
+	}
+
+	return latest
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"runtime"
 	"strconv"
 	"strings"
 )
@@ -17,20 +18,36 @@ const (
 	Head
 )
 
+// Version is a Go toolchain version such as 1.21.0, 1.21rc1 and 1.20. The
+// versions are ordered in the same way as the go/version package, where the
+// language version 1.21 precedes 1.21rc1, which precedes the release 1.21.0.
+//
+// Prereleases of patch releases such as 1.9.2rc2, which go/version does not
+// accept, are also accepted and precede their patch releases.
 type Version struct {
-	Type    VersionType
-	Major   int
-	Minor   int
-	Patch   int
-	Release int // Release number of beta or rc.
+	Type     VersionType
+	Major    int
+	Minor    int
+	Patch    int
+	HasPatch bool // Patch is written explicitly, e.g. 1.21.0 rather than 1.21.
+	Release  int  // Release number of beta or rc.
+
+	// OS and Arch are the platform of a toolchain that is not for the running
+	// platform, such as linux/386 on linux/amd64. Both are empty for the
+	// running platform.
+	OS   string
+	Arch string // GOARCH
 }
 
 var ErrVersionSyntax = errors.New("invalid version syntax")
 
-var versionRegexp = regexp.MustCompile(`^(1)\.([0-9]+)(\.([0-9]+))?((beta|rc)([0-9]+))?$`)
+var versionRegexp = regexp.MustCompile(`^(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)(\.(0|[1-9][0-9]*))?((beta|rc)(0|[1-9][0-9]*))?(\.([a-z0-9]+)-([a-z0-9]+))?$`)
 
 const headVersion = "go-head"
 
+// ParseVersion parses a version such as 1.22.7, go1.24rc1 or
+// go1.22.7.linux-386. The platform is cleared if it is the running platform,
+// so go1.22.7.linux-amd64 is parsed as 1.22.7 on linux/amd64.
 func ParseVersion(s string) (*Version, error) {
 	if s == headVersion {
 		return &Version{Type: Head}, nil
@@ -39,7 +56,7 @@ func ParseVersion(s string) (*Version, error) {
 	s = strings.TrimPrefix(s, "go")
 
 	matches := versionRegexp.FindStringSubmatch(s)
-	if len(matches) != 8 {
+	if len(matches) != 11 {
 		return nil, ErrVersionSyntax
 	}
 
@@ -54,7 +71,8 @@ func ParseVersion(s string) (*Version, error) {
 	}
 
 	var patch int
-	if matches[4] != "" {
+	hasPatch := matches[3] != ""
+	if hasPatch {
 		p, err := strconv.Atoi(matches[4])
 		if err != nil {
 			return nil, ErrVersionSyntax
@@ -85,34 +103,112 @@ func ParseVersion(s string) (*Version, error) {
 		return nil, ErrVersionSyntax
 	}
 
-	return &Version{
-		Major:   major,
-		Minor:   minor,
-		Patch:   patch,
-		Type:    typ,
-		Release: release,
-	}, nil
+	v := &Version{
+		Major:    major,
+		Minor:    minor,
+		Patch:    patch,
+		HasPatch: hasPatch,
+		Type:     typ,
+		Release:  release,
+	}
+	if matches[8] != "" {
+		v = v.ForPlatform(matches[9], matches[10])
+	}
+
+	return v, nil
+}
+
+// ForPlatform returns a copy of v for the specified platform. The platform is
+// cleared if it is the running platform.
+func (v *Version) ForPlatform(goos, goarch string) *Version {
+	nv := *v
+	if goos == runtime.GOOS && goarch == runtime.GOARCH {
+		nv.OS = ""
+		nv.Arch = ""
+	} else {
+		nv.OS = goos
+		nv.Arch = goarch
+	}
+
+	return &nv
+}
+
+// Platform returns the OS and the architecture of v.
+func (v *Version) Platform() (goos, goarch string) {
+	if v.Arch == "" {
+		return runtime.GOOS, runtime.GOARCH
+	}
+
+	return v.OS, v.Arch
+}
+
+// releaseFilter returns the filter that selects archives of v.
+func (v *Version) releaseFilter() ReleaseFilter {
+	goos, goarch := v.Platform()
+	return ReleaseFilter{
+		OS:   goos,
+		Arch: goarch,
+		Kind: "archive",
+	}
 }
 
 func (v *Version) String() string {
+	if v.Type == Head || v.Arch == "" {
+		return v.versionString()
+	}
+
+	return v.versionString() + "." + v.OS + "-" + v.Arch
+}
+
+func (v *Version) versionString() string {
+	var pre string
 	switch v.Type {
 	case Stable:
-		if v.Patch > 0 {
-			return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
-		}
-
-		return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+		pre = ""
 	case Beta:
-		return fmt.Sprintf("%d.%dbeta%d", v.Major, v.Minor, v.Release)
+		pre = fmt.Sprintf("beta%d", v.Release)
 	case RC:
-		return fmt.Sprintf("%d.%drc%d", v.Major, v.Minor, v.Release)
+		pre = fmt.Sprintf("rc%d", v.Release)
 	case Head:
 		return headVersion
+	default:
+		return ""
+	}
+
+	if v.hasPatch() {
+		return fmt.Sprintf("%d.%d.%d%s", v.Major, v.Minor, v.Patch, pre)
+	}
+
+	return fmt.Sprintf("%d.%d%s", v.Major, v.Minor, pre)
+}
+
+func (v *Version) hasPatch() bool {
+	return v.HasPatch || v.Patch > 0
+}
+
+// IsLang reports whether v is a language version such as 1.21, which is not a
+// release since Go 1.21.
+func (v *Version) IsLang() bool {
+	return v.Type == Stable && !v.hasPatch() && v.Minor >= 21
+}
+
+// comparablePatch returns the patch number to compare, which is -1 if the
+// patch is omitted. As go/version does, an omitted patch of a minor version
+// before 21 is the same as 0.
+func (v *Version) comparablePatch() int {
+	if v.hasPatch() {
+		return v.Patch
 	}
 
-	return ""
+	if v.Type == Stable && v.Minor < 21 {
+		return 0
+	}
+
+	return -1
 }
 
+// CompareVersion compares x and y in the same way as the go/version package.
+// The platforms are not compared.
 func CompareVersion(x, y *Version) int {
 	if x.Type == Head && y.Type == Head {
 		return 0
@@ -130,35 +226,47 @@ func CompareVersion(x, y *Version) int {
 		return compareInt(x.Minor, y.Minor)
 	}
 
-	if x.Type != y.Type {
-		if x.Type == Stable {
-			return 1
-		}
-
-		if x.Type == Beta {
-			return -1
-		}
+	if c := compareInt(x.comparablePatch(), y.comparablePatch()); c != 0 {
+		return c
+	}
 
-		if y.Type == Stable {
-			return -1
+	if x.Type != y.Type {
+		// a prerelease of a patch release precedes the patch release
+		if x.hasPatch() || y.hasPatch() {
+			if x.Type == Stable {
+				return 1
+			}
+			if y.Type == Stable {
+				return -1
+			}
 		}
 
-		if y.Type == Beta {
-			return 1
-		}
+		// a language version precedes prereleases, and beta precedes rc
+		return compareInt(int(x.Type), int(y.Type))
 	}
 
-	if x.Type == Stable {
-		return compareInt(x.Patch, y.Patch)
-	} else {
-		return compareInt(x.Release, y.Release)
-	}
+	return compareInt(x.Release, y.Release)
 }
 
 func EqualVersion(x, y *Version) bool {
 	return CompareVersion(x, y) == 0
 }
 
+// EqualMinorVersion reports whether x and y belong to the same minor line,
+// e.g. 1.22rc1, 1.22.0 and 1.22.7.
+func EqualMinorVersion(x, y *Version) bool {
+	if x.Type == Head || y.Type == Head {
+		return x.Type == y.Type
+	}
+
+	return x.Major == y.Major && x.Minor == y.Minor
+}
+
+// SamePlatform reports whether x and y are for the same platform.
+func SamePlatform(x, y *Version) bool {
+	return x.OS == y.OS && x.Arch == y.Arch
+}
+
 func compareInt(x, y int) int {
 	if x > y {
 		return 1
@@ -1,7 +1,10 @@
 package env
 
 import (
+	"go/version"
 	"reflect"
+	"runtime"
+	"strings"
 	"testing"
 )
 
@@ -35,11 +38,12 @@ var versionTests = map[string]struct {
 	"go1.15.10": {
 		s: "go1.15.10",
 		v: &Version{
-			Type:    Stable,
-			Major:   1,
-			Minor:   15,
-			Patch:   10,
-			Release: 0,
+			Type:     Stable,
+			Major:    1,
+			Minor:    15,
+			Patch:    10,
+			HasPatch: true,
+			Release:  0,
 		},
 		err: nil,
 	},
@@ -68,22 +72,73 @@ var versionTests = map[string]struct {
 	"go1.14.1beta4": {
 		s: "go1.14.1beta4",
 		v: &Version{
-			Type:    Beta,
-			Major:   1,
-			Minor:   14,
-			Patch:   1,
-			Release: 4,
+			Type:     Beta,
+			Major:    1,
+			Minor:    14,
+			Patch:    1,
+			HasPatch: true,
+			Release:  4,
 		},
 		err: nil,
 	},
 	"go1.13.14rc15": {
 		s: "go1.13.14rc15",
 		v: &Version{
-			Type:    RC,
+			Type:     RC,
+			Major:    1,
+			Minor:    13,
+			Patch:    14,
+			HasPatch: true,
+			Release:  15,
+		},
+		err: nil,
+	},
+	"go1.21": {
+		s: "go1.21",
+		v: &Version{
+			Type:    Stable,
 			Major:   1,
-			Minor:   13,
-			Patch:   14,
-			Release: 15,
+			Minor:   21,
+			Patch:   0,
+			Release: 0,
+		},
+		err: nil,
+	},
+	"go1.21.0": {
+		s: "go1.21.0",
+		v: &Version{
+			Type:     Stable,
+			Major:    1,
+			Minor:    21,
+			Patch:    0,
+			HasPatch: true,
+			Release:  0,
+		},
+		err: nil,
+	},
+	"go2.0.1": {
+		s: "go2.0.1",
+		v: &Version{
+			Type:     Stable,
+			Major:    2,
+			Minor:    0,
+			Patch:    1,
+			HasPatch: true,
+			Release:  0,
+		},
+		err: nil,
+	},
+	"go1.22.7.plan9-386": {
+		s: "go1.22.7.plan9-386",
+		v: &Version{
+			Type:     Stable,
+			Major:    1,
+			Minor:    22,
+			Patch:    7,
+			HasPatch: true,
+			Release:  0,
+			OS:       "plan9",
+			Arch:     "386",
 		},
 		err: nil,
 	},
@@ -123,6 +178,21 @@ var versionTests = map[string]struct {
 		v:   nil,
 		err: ErrVersionSyntax,
 	},
+	"go1.22.7.linux": {
+		s:   "go1.22.7.linux",
+		v:   nil,
+		err: ErrVersionSyntax,
+	},
+	"go1.021": {
+		s:   "go1.021",
+		v:   nil,
+		err: ErrVersionSyntax,
+	},
+	"go1.21alpha1": {
+		s:   "go1.21alpha1",
+		v:   nil,
+		err: ErrVersionSyntax,
+	},
 	"go10": {
 		s:   "go10",
 		v:   nil,
@@ -144,3 +214,194 @@ func Test_ParseVersion(t *testing.T) {
 		})
 	}
 }
+
+func Test_Version_String(t *testing.T) {
+	for name, tt := range versionTests {
+		if tt.err != nil {
+			continue
+		}
+		t.Run(name, func(t *testing.T) {
+			want := normalizeVersion(tt.s)
+			if got := tt.v.String(); got != want {
+				t.Errorf("String(): got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+// orderedVersions is sorted in ascending order.
+var orderedVersions = []string{
+	"1.2",
+	"1.9rc1",
+	"1.9",
+	"1.9.1",
+	"1.9.2rc2",
+	"1.9.2",
+	"1.20beta1",
+	"1.20rc1",
+	"1.20",
+	"1.20.1",
+	"1.21",
+	"1.21rc1",
+	"1.21rc2",
+	"1.21.0",
+	"1.21.1",
+	"1.22rc1",
+	"2.0.0",
+	"go-head",
+}
+
+func Test_CompareVersion(t *testing.T) {
+	for i, xs := range orderedVersions {
+		for j, ys := range orderedVersions {
+			x, err := ParseVersion(xs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			y, err := ParseVersion(ys)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := compareInt(i, j)
+			if got := CompareVersion(x, y); got != want {
+				t.Errorf("CompareVersion(%v, %v): got %v, want %v", xs, ys, got, want)
+			}
+		}
+	}
+}
+
+var equalVersionTests = [][2]string{
+	{"1.20", "1.20.0"},
+	{"1.2", "1.2.0"},
+	{"1.20.1.plan9-386", "1.20.1"},
+}
+
+func Test_EqualVersion(t *testing.T) {
+	for _, tt := range equalVersionTests {
+		x, err := ParseVersion(tt[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		y, err := ParseVersion(tt[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !EqualVersion(x, y) {
+			t.Errorf("EqualVersion(%v, %v): got false, want true", tt[0], tt[1])
+		}
+	}
+
+	x, _ := ParseVersion("1.21")
+	y, _ := ParseVersion("1.21.0")
+	if EqualVersion(x, y) {
+		t.Errorf("EqualVersion(1.21, 1.21.0): got true, want false")
+	}
+}
+
+// hostSuffix is the platform suffix of the running platform, such as
+// ".linux-amd64".
+var hostSuffix = "." + runtime.GOOS + "-" + runtime.GOARCH
+
+// normalizeVersion returns the form of a valid version string s that
+// Version.String returns. The "go" prefix and the suffix of the running
+// platform are removed, since a version for the running platform has no
+// platform.
+func normalizeVersion(s string) string {
+	if s == headVersion {
+		return s
+	}
+
+	return strings.TrimSuffix(strings.TrimPrefix(s, "go"), hostSuffix)
+}
+
+func Test_ParseVersion_hostPlatform(t *testing.T) {
+	s := "go1.22.7" + hostSuffix
+
+	v, err := ParseVersion(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v.OS != "" || v.Arch != "" {
+		t.Errorf("ParseVersion(%v): got platform %v-%v, want empty", s, v.OS, v.Arch)
+	}
+	if got, want := v.String(), "1.22.7"; got != want {
+		t.Errorf("ParseVersion(%v).String(): got %v, want %v", s, got, want)
+	}
+}
+
+// goVersion returns the go/version form of v, or false if go/version does not
+// accept v.
+func goVersion(v *Version) (string, bool) {
+	if v.Type == Head || (v.Type != Stable && v.hasPatch()) {
+		return "", false
+	}
+
+	return "go" + v.versionString(), true
+}
+
+func FuzzParseVersion(f *testing.F) {
+	for _, tt := range versionTests {
+		f.Add(tt.s)
+	}
+	f.Add("go1.22.7" + hostSuffix)
+
+	f.Fuzz(func(t *testing.T, s string) {
+		v, err := ParseVersion(s)
+		if err != nil {
+			return
+		}
+
+		// the string is normalized, and the normalized string is parsed to
+		// the same version
+		want := normalizeVersion(s)
+		if got := v.String(); got != want {
+			t.Errorf("ParseVersion(%q).String(): got %q, want %q", s, got, want)
+		}
+		if rv, err := ParseVersion(v.String()); err != nil || !reflect.DeepEqual(rv, v) {
+			t.Errorf("ParseVersion(%q): got %v, %v, want %v", v.String(), rv, err, v)
+		}
+
+		if gv, ok := goVersion(v); ok && !version.IsValid(gv) {
+			t.Errorf("ParseVersion(%q): go/version does not accept %q", s, gv)
+		}
+	})
+}
+
+func FuzzCompareVersion(f *testing.F) {
+	for _, x := range orderedVersions {
+		for _, y := range orderedVersions {
+			f.Add(x, y)
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, xs, ys string) {
+		x, err := ParseVersion(xs)
+		if err != nil {
+			return
+		}
+		y, err := ParseVersion(ys)
+		if err != nil {
+			return
+		}
+
+		if got := CompareVersion(x, y); got != -CompareVersion(y, x) {
+			t.Errorf("CompareVersion(%q, %q) is not antisymmetric", xs, ys)
+		}
+
+		gx, ok := goVersion(x)
+		if !ok {
+			return
+		}
+		gy, ok := goVersion(y)
+		if !ok {
+			return
+		}
+
+		if got, want := CompareVersion(x, y), version.Compare(gx, gy); got != want {
+			t.Errorf("CompareVersion(%q, %q): got %v, want %v", xs, ys, got, want)
+		}
+	})
+}
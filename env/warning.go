@@ -0,0 +1,28 @@
+package env
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Warning describes an entry skipped because its version cannot be parsed,
+// such as a release in the download list or a directory in the root.
+type Warning struct {
+	Source string // the download list file name or the root directory
+	Name   string // the version string or the directory name
+	Err    error
+}
+
+func (w *Warning) Error() string {
+	return fmt.Sprintf("%s: %s is skipped: %v", w.Source, w.Name, w.Err)
+}
+
+func (w *Warning) Unwrap() error {
+	return w.Err
+}
+
+// Warnings returns the warnings reported while loading the installed versions
+// and the releases.
+func (env *Env) Warnings() []*Warning {
+	return slices.Concat(env.installWarnings, env.releaseWarnings)
+}
@@ -5,7 +5,10 @@ go 1.25rc2
 require (
 	github.com/cheggaaa/pb/v3 v3.1.7
 	github.com/kechako/table v0.0.0-20250725025942-a3a01d5ea207
+	github.com/mattn/go-isatty v0.0.20
 	github.com/spf13/cobra v1.9.1
+	github.com/spf13/pflag v1.0.7
+	golang.org/x/sys v0.34.0
 )
 
 require (
@@ -13,9 +16,6 @@ require (
 	github.com/fatih/color v1.18.0 // indirect
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/mattn/go-colorable v0.1.14 // indirect
-	github.com/mattn/go-isatty v0.0.20 // indirect
 	github.com/mattn/go-runewidth v0.0.16 // indirect
 	github.com/rivo/uniseg v0.4.7 // indirect
-	github.com/spf13/pflag v1.0.7 // indirect
-	golang.org/x/sys v0.34.0 // indirect
 )